@@ -0,0 +1,293 @@
+package GoInk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const (
+	ACCESS_LOG_COMMON   = "common"
+	ACCESS_LOG_COMBINED = "combined"
+	ACCESS_LOG_JSON     = "json"
+)
+
+// AccessEntry is one access log record, used as data of custom format template.
+type AccessEntry struct {
+	Time      time.Time     `json:"time"`
+	Method    string        `json:"method"`
+	Path      string        `json:"path"`
+	Proto     string        `json:"proto"`
+	Status    int           `json:"status"`
+	Bytes     int64         `json:"bytes"`
+	Latency   time.Duration `json:"latency"`
+	Ip        string        `json:"ip"`
+	UserAgent string        `json:"user_agent"`
+	Referer   string        `json:"referer"`
+	RequestId string        `json:"request_id,omitempty"`
+	User      string        `json:"user,omitempty"`
+}
+
+// AccessLogOption defines access log settings.
+type AccessLogOption struct {
+	// ACCESS_LOG_COMMON, ACCESS_LOG_COMBINED, ACCESS_LOG_JSON or text/template string of AccessEntry
+	Format string
+	// Log writer, os.Stdout by default
+	Writer io.Writer
+}
+
+// NewAccessLogOption returns access log option from config:
+//
+//	log.access_format    "combined" by default
+//	log.access_file      log file path, stdout if empty
+//	log.access_max_size  bytes to rotate log file, 100MB by default
+//	log.access_backups   rotated files to keep, 7 by default
+func NewAccessLogOption(cfg *Config) (*AccessLogOption, error) {
+	opt := &AccessLogOption{Format: cfg.String("log.access_format"), Writer: os.Stdout}
+	if opt.Format == "" {
+		opt.Format = ACCESS_LOG_COMBINED
+	}
+	if file := cfg.String("log.access_file"); file != "" {
+		size := int64(cfg.Int("log.access_max_size"))
+		if size <= 0 {
+			size = 100 << 20
+		}
+		backups := 7
+		if cfg.String("log.access_backups") != "" {
+			backups = cfg.Int("log.access_backups")
+		}
+		w, e := NewRotateWriter(file, size, backups)
+		if e != nil {
+			return nil, e
+		}
+		opt.Writer = w
+	}
+	return opt, nil
+}
+
+// AccessLog returns middleware handler writing access log after context end.
+// Nil option loads settings by NewAccessLogOption from app config.
+func AccessLog(opt *AccessLogOption) Handler {
+	var (
+		once   sync.Once
+		format func(w io.Writer, entry *AccessEntry) error
+		lock   sync.Mutex
+	)
+	return func(ctx *Context) {
+		once.Do(func() {
+			if opt == nil {
+				var e error
+				if opt, e = NewAccessLogOption(ctx.app.config); e != nil {
+					panic(e)
+				}
+			}
+			format = accessFormatter(opt.Format)
+		})
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: ctx.Response}
+		ctx.Response = sw
+		ctx.On(CONTEXT_END, func() {
+			entry := &AccessEntry{
+				Time:      start,
+				Method:    ctx.Method,
+				Path:      ctx.RequestUrl,
+				Proto:     ctx.Request.Proto,
+				Status:    sw.status,
+				Bytes:     sw.bytes,
+				Latency:   time.Since(start),
+				Ip:        ctx.Ip,
+				UserAgent: ctx.UserAgent,
+				Referer:   ctx.Referer,
+				RequestId: ctx.RequestId(),
+			}
+			if entry.Status == 0 {
+				entry.Status = ctx.Status
+			}
+			if ctx.principal != nil {
+				entry.User = fmt.Sprint(ctx.principal)
+				if claims, ok := ctx.principal.(JwtClaims); ok {
+					entry.User = claims.String("sub")
+				}
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if e := format(opt.Writer, entry); e != nil {
+				ctx.Logger().Error("access log error", "error", e)
+			}
+		})
+	}
+}
+
+func accessFormatter(format string) func(w io.Writer, entry *AccessEntry) error {
+	switch format {
+	case ACCESS_LOG_COMMON, ACCESS_LOG_COMBINED:
+		combined := format == ACCESS_LOG_COMBINED
+		return func(w io.Writer, entry *AccessEntry) error {
+			user := entry.User
+			if user == "" {
+				user = "-"
+			}
+			line := fmt.Sprintf(`%s - %s [%s] "%s %s %s" %d %s`,
+				entry.Ip, clfQuote(user), entry.Time.Format("02/Jan/2006:15:04:05 -0700"),
+				entry.Method, clfQuote(entry.Path), entry.Proto, entry.Status, clfBytes(entry.Bytes))
+			if combined {
+				line += fmt.Sprintf(` "%s" "%s"`, clfQuote(entry.Referer), clfQuote(entry.UserAgent))
+			}
+			_, e := io.WriteString(w, line+"\n")
+			return e
+		}
+	case ACCESS_LOG_JSON:
+		return func(w io.Writer, entry *AccessEntry) error {
+			bytes, e := json.Marshal(entry)
+			if e != nil {
+				return e
+			}
+			_, e = w.Write(append(bytes, '\n'))
+			return e
+		}
+	}
+	tpl := template.Must(template.New("access").Parse(format))
+	return func(w io.Writer, entry *AccessEntry) error {
+		var buf strings.Builder
+		if e := tpl.Execute(&buf, entry); e != nil {
+			return e
+		}
+		_, e := io.WriteString(w, strings.TrimRight(buf.String(), "\n")+"\n")
+		return e
+	}
+}
+
+// clfQuote escapes quotes and control chars in log field.
+func clfQuote(str string) string {
+	q := strconv.QuoteToASCII(str)
+	return q[1 : len(q)-1]
+}
+
+func clfBytes(n int64) string {
+	if n == 0 {
+		return "-"
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// statusWriter records response status and written bytes.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	if sw.status == 0 {
+		sw.status = status
+	}
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(p []byte) (int, error) {
+	if sw.status == 0 {
+		sw.status = http.StatusOK
+	}
+	n, e := sw.ResponseWriter.Write(p)
+	sw.bytes += int64(n)
+	return n, e
+}
+
+func (sw *statusWriter) Flush() {
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close closes wrapped response writer if it's closer.
+func (sw *statusWriter) Close() error {
+	if c, ok := sw.ResponseWriter.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Unwrap returns native response writer for http.ResponseController.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// RotateWriter writes to file and rotates it when size exceeds max size.
+// Rotated files are named as file.1, file.2 and so on, file.1 is the newest.
+type RotateWriter struct {
+	file    string
+	maxSize int64
+	backups int
+	size    int64
+	fp      *os.File
+	lock    sync.Mutex
+}
+
+// NewRotateWriter opens file for appending with max size and kept backups count.
+func NewRotateWriter(file string, maxSize int64, backups int) (*RotateWriter, error) {
+	rw := &RotateWriter{file: file, maxSize: maxSize, backups: backups}
+	if e := rw.open(); e != nil {
+		return nil, e
+	}
+	return rw, nil
+}
+
+func (rw *RotateWriter) open() error {
+	fp, e := os.OpenFile(rw.file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if e != nil {
+		return e
+	}
+	info, e := fp.Stat()
+	if e != nil {
+		fp.Close()
+		return e
+	}
+	rw.fp, rw.size = fp, info.Size()
+	return nil
+}
+
+// Write writes data to file, rotating it before if it will be too large.
+func (rw *RotateWriter) Write(p []byte) (int, error) {
+	rw.lock.Lock()
+	defer rw.lock.Unlock()
+	if rw.maxSize > 0 && rw.size > 0 && rw.size+int64(len(p)) > rw.maxSize {
+		if e := rw.rotate(); e != nil {
+			return 0, e
+		}
+	}
+	n, e := rw.fp.Write(p)
+	rw.size += int64(n)
+	return n, e
+}
+
+func (rw *RotateWriter) rotate() error {
+	if e := rw.fp.Close(); e != nil {
+		return e
+	}
+	if rw.backups > 0 {
+		os.Remove(rw.file + "." + strconv.Itoa(rw.backups))
+		for i := rw.backups - 1; i > 0; i-- {
+			os.Rename(rw.file+"."+strconv.Itoa(i), rw.file+"."+strconv.Itoa(i+1))
+		}
+		if e := os.Rename(rw.file, rw.file+".1"); e != nil {
+			return e
+		}
+	} else if e := os.Truncate(rw.file, 0); e != nil {
+		return e
+	}
+	return rw.open()
+}
+
+// Close closes log file.
+func (rw *RotateWriter) Close() error {
+	rw.lock.Lock()
+	defer rw.lock.Unlock()
+	return rw.fp.Close()
+}
@@ -2,20 +2,32 @@ package GoInk
 
 import (
 	"fmt"
+	"net"
 	"net/http"
+	"os"
 	"runtime/debug"
 	"strings"
+	"sync"
+	"time"
 )
 
 // App struct is top level application.
 // It contains Router,View,Config and private fields.
 type App struct {
-	router  *Router
-	routerC map[string]*routerCache
-	view    *View
-	middle  []Handler
-	inter   map[string]Handler
-	config  *Config
+	router    *Router
+	routerC   map[string]*routerCache
+	routerL   sync.RWMutex
+	view      *View
+	middle    []Handler
+	inter     map[string]Handler
+	config    *Config
+	validator *Validator
+	policy    PolicyChecker
+	proxies   []*net.IPNet
+	logger    Logger
+	metrics   *Metrics
+	stats     *httpMetrics
+	flashKey  string
 }
 
 // New creates an App instance.
@@ -29,6 +41,14 @@ func New() *App {
 	a.inter = make(map[string]Handler)
 	a.config, _ = NewConfig("config.json")
 	a.view = NewView(a.config.StringOr("app.view_dir", "view"))
+	a.validator = NewValidator()
+	a.flashKey = randomString(32)
+	a.logger = NewLogger(os.Stderr, ParseLogLevel(a.config.String("log.level")))
+	a.metrics = NewMetrics()
+	a.stats = newHttpMetrics(a.metrics)
+	if e := a.TrustProxy(splitConfig(a.config.String("app.trusted_proxies"))...); e != nil {
+		a.logger.Error("invalid app.trusted_proxies", "error", e)
+	}
 	return a
 }
 
@@ -48,8 +68,16 @@ func (app *App) View() *View {
 	return app.view
 }
 
+// Validator returns global *Validator instance.
+// Custom rules and messages are registered to it.
+func (app *App) Validator() *Validator {
+	return app.validator
+}
+
 func (app *App) handler(res http.ResponseWriter, req *http.Request) {
 	context := NewContext(app, res, req)
+	app.stats.inFlight.Inc()
+	defer app.stats.observe(context, time.Now())
 
 	defer func() {
 		e := recover()
@@ -59,8 +87,8 @@ func (app *App) handler(res http.ResponseWriter, req *http.Request) {
 		}
 		context.Body = []byte(fmt.Sprint(e))
 		context.Status = 503
-		println(string(context.Body))
-		debug.PrintStack()
+		context.Logger().Error("panic recovered", "error", e, "stack", string(debug.Stack()))
+		app.stats.panics.Inc(metricMethod(context), metricRoute(context))
 		if _, ok := app.inter["recover"]; ok {
 			app.inter["recover"](context)
 		}
@@ -70,11 +98,11 @@ func (app *App) handler(res http.ResponseWriter, req *http.Request) {
 		context = nil
 	}()
 
-	if _, ok := app.inter["static"]; ok {
-		app.inter["static"](context)
-		if context.IsEnd {
-			return
-		}
+	// route is matched before middleware, so route options as body limit apply to middleware input
+	route := app.findRoute(req)
+	if route != nil {
+		context.route = route.route
+		context.routeParams = route.param
 	}
 
 	if len(app.middle) > 0 {
@@ -87,29 +115,21 @@ func (app *App) handler(res http.ResponseWriter, req *http.Request) {
 	}
 
 	if context.IsSend {
+		if !context.IsEnd {
+			context.End()
+		}
 		return
 	}
-	var (
-		params map[string]string
-		fn     []Handler
-		url    = req.URL.Path
-	)
-
-	if _, ok := app.routerC[url]; ok {
-		params = app.routerC[url].param
-		fn = app.routerC[url].fn
-	} else {
-		params, fn = app.router.Find(url, req.Method)
-	}
-	if params != nil && fn != nil {
-		context.routeParams = params
 
-		rc := new(routerCache)
-		rc.param = params
-		rc.fn = fn
-		app.routerC[url] = rc
+	if _, ok := app.inter["static"]; ok {
+		app.inter["static"](context)
+		if context.IsEnd {
+			return
+		}
+	}
 
-		for _, f := range fn {
+	if route != nil {
+		for _, f := range route.route.fn {
 			f(context)
 			if context.IsEnd {
 				break
@@ -119,7 +139,7 @@ func (app *App) handler(res http.ResponseWriter, req *http.Request) {
 			context.End()
 		}
 	} else {
-		println("router is missing at " + req.URL.Path)
+		context.Logger().Info("router is missing")
 		context.Status = 404
 		if _, ok := app.inter["notfound"]; ok {
 			app.inter["notfound"](context)
@@ -134,6 +154,28 @@ func (app *App) handler(res http.ResponseWriter, req *http.Request) {
 	context = nil
 }
 
+// findRoute returns matched route and params of request by router cache, or nil.
+func (app *App) findRoute(req *http.Request) *routerCache {
+	key := req.Method + " " + req.URL.Path
+	app.routerL.RLock()
+	rc, cached := app.routerC[key]
+	app.routerL.RUnlock()
+	if cached {
+		app.stats.cacheHits.Inc()
+		return rc
+	}
+	app.stats.cacheMisses.Inc()
+	route, params := app.router.match(req.URL.Path, req.Method)
+	if route == nil || route.fn == nil {
+		return nil
+	}
+	rc = &routerCache{param: params, route: route}
+	app.routerL.Lock()
+	app.routerC[key] = rc
+	app.routerL.Unlock()
+	return rc
+}
+
 // ServeHTTP is HTTP server implement method. It makes App compatible to native http handler.
 func (app *App) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	app.handler(res, req)
@@ -142,7 +184,7 @@ func (app *App) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 // Run http server and listen on config value or 9001 by default.
 func (app *App) Run() {
 	addr := app.config.StringOr("app.server", "localhost:9001")
-	println("http server run at " + addr)
+	app.logger.Info("http server run", "addr", addr)
 	e := http.ListenAndServe(addr, app)
 	panic(e)
 }
@@ -177,8 +219,13 @@ func (app *App) Delete(key string, fn ...Handler) {
 	app.router.Delete(key, fn...)
 }
 
+// Register OPTIONS handlers to router.
+func (app *App) Options(key string, fn ...Handler) {
+	app.router.Options(key, fn...)
+}
+
 // Register handlers to router with custom methods and pattern string.
-// Support GET,POST,PUT and DELETE methods.
+// Support GET,POST,PUT,DELETE and OPTIONS methods.
 // Usage:
 //     app.Route("GET,POST","/test",handler)
 //
@@ -194,14 +241,20 @@ func (app *App) Route(method string, key string, fn ...Handler) {
 			app.Put(key, fn...)
 		case "DELETE":
 			app.Delete(key, fn...)
+		case "OPTIONS":
+			app.Options(key, fn...)
 		default:
-			println("unknow route method " + m)
+			app.logger.Error("unknown route method", "method", m, "pattern", key)
 		}
 	}
 }
 
 // Register static file handler.
-// It's invoked before route handler after middleware handler.
+// It's invoked after middleware handlers and before route handler,
+// so middleware as AccessLog, RequestId, SecureHeaders and Cors apply to static files.
+// Use StaticHandler for built-in file server:
+//     app.Static(GoInk.StaticHandler(nil))
+//
 func (app *App) Static(h Handler) {
 	app.inter["static"] = h
 }
@@ -0,0 +1,118 @@
+package GoInk
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// BasicVerifier checks user name and password.
+// It returns authenticated principal and true if valid.
+type BasicVerifier func(ctx *Context, user string, password string) (interface{}, bool)
+
+// TokenVerifier checks bearer token or api key.
+// It returns authenticated principal and true if valid.
+type TokenVerifier func(ctx *Context, token string) (interface{}, bool)
+
+// Principal returns authenticated principal of this context, or nil.
+func (ctx *Context) Principal() interface{} {
+	return ctx.principal
+}
+
+// SetPrincipal sets authenticated principal of this context.
+func (ctx *Context) SetPrincipal(p interface{}) {
+	ctx.principal = p
+}
+
+// SecureCompare compares two strings in constant time.
+// Strings are hashed first, so the time doesn't leak length either.
+func SecureCompare(a string, b string) bool {
+	ha := sha256.Sum256([]byte(a))
+	hb := sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
+
+// BasicAccounts returns BasicVerifier of user-password map.
+// Passwords are compared in constant time, the principal is user name.
+func BasicAccounts(accounts map[string]string) BasicVerifier {
+	return func(ctx *Context, user string, password string) (interface{}, bool) {
+		expect, ok := accounts[user]
+		if !SecureCompare(password, expect) || !ok {
+			return nil, false
+		}
+		return user, true
+	}
+}
+
+// unauthorized sets WWW-Authenticate header and throws 401.
+func unauthorized(ctx *Context, challenge string) {
+	ctx.Header["WWW-Authenticate"] = challenge
+	ctx.Throw(http.StatusUnauthorized, "unauthorized")
+}
+
+// BasicAuth returns handler of http basic authentication.
+func BasicAuth(realm string, verify BasicVerifier) Handler {
+	challenge := "Basic realm=" + strconv.Quote(realm) + `, charset="UTF-8"`
+	return func(ctx *Context) {
+		user, password, ok := ctx.Request.BasicAuth()
+		if !ok {
+			unauthorized(ctx, challenge)
+			return
+		}
+		p, ok := verify(ctx, user, password)
+		if !ok {
+			unauthorized(ctx, challenge)
+			return
+		}
+		ctx.principal = p
+	}
+}
+
+// BearerAuth returns handler of bearer token authentication by Authorization header.
+func BearerAuth(realm string, verify TokenVerifier) Handler {
+	challenge := "Bearer realm=" + strconv.Quote(realm)
+	return func(ctx *Context) {
+		auth := ctx.GetHeader("Authorization")
+		if len(auth) < 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+			unauthorized(ctx, challenge)
+			return
+		}
+		p, ok := verify(ctx, strings.TrimSpace(auth[7:]))
+		if !ok {
+			unauthorized(ctx, challenge+`, error="invalid_token"`)
+			return
+		}
+		ctx.principal = p
+	}
+}
+
+// ApiKeyAuth returns handler of api key authentication.
+// The key is read from header, then query param if header is empty.
+// Empty header or query name skips that source.
+func ApiKeyAuth(header string, query string, verify TokenVerifier) Handler {
+	challenge := "ApiKey"
+	if header != "" {
+		challenge += " header=" + strconv.Quote(header)
+	}
+	return func(ctx *Context) {
+		var key string
+		if header != "" {
+			key = ctx.GetHeader(header)
+		}
+		if key == "" && query != "" {
+			key = ctx.Request.URL.Query().Get(query)
+		}
+		if key == "" {
+			unauthorized(ctx, challenge)
+			return
+		}
+		p, ok := verify(ctx, key)
+		if !ok {
+			unauthorized(ctx, challenge)
+			return
+		}
+		ctx.principal = p
+	}
+}
@@ -0,0 +1,135 @@
+package GoInk
+
+import (
+	"net/http"
+)
+
+// PolicyChecker checks whether context principal has permission or role.
+type PolicyChecker func(ctx *Context, perm string) bool
+
+// Permitter is implemented by principal checking its own permissions.
+// It's used if no PolicyChecker is set to App.
+type Permitter interface {
+	HasPermission(perm string) bool
+}
+
+// Policy sets global permission checker of routes.
+func (app *App) Policy(p PolicyChecker) {
+	app.policy = p
+}
+
+// Router returns global *Router instance.
+// Its register methods return *Route, so permissions can be required:
+//
+//	app.Router().Post("/admin/user/", userHandler).Require("user.edit")
+func (app *App) Router() *Router {
+	return app.router
+}
+
+// Group returns route group with prefix and shared handlers.
+func (app *App) Group(prefix string, fn ...Handler) *RouteGroup {
+	return app.router.Group(prefix, fn...)
+}
+
+// Authorize checks all permissions by app policy.
+// It returns false if principal is nil or any permission is denied.
+func (ctx *Context) Authorize(perms ...string) bool {
+	for _, perm := range perms {
+		if ctx.app.policy != nil {
+			if !ctx.app.policy(ctx, perm) {
+				return false
+			}
+			continue
+		}
+		p, ok := ctx.principal.(Permitter)
+		if !ok || !p.HasPermission(perm) {
+			return false
+		}
+	}
+	return true
+}
+
+// Require adds permissions to route.
+// The route throws 403 if context is not authorized for all of them.
+// The check runs before all route handlers, so authentication must be done by middleware,
+// or by shared handlers of RouteGroup, which run before the check:
+//
+//	admin := app.Group("/admin", GoInk.BasicAuth("admin", verifier))
+//	admin.Post("/user/delete/", loadUser, deleteUser).Require("user.delete")
+func (r *Route) Require(perms ...string) *Route {
+	if len(perms) < 1 {
+		return r
+	}
+	if len(r.perms) < 1 {
+		fn := make([]Handler, 0, len(r.fn)+1)
+		fn = append(append(append(fn, r.fn[:r.shared]...), r.authorize), r.fn[r.shared:]...)
+		r.fn = fn
+	}
+	r.perms = append(r.perms, perms...)
+	return r
+}
+
+func (r *Route) authorize(ctx *Context) {
+	if !ctx.Authorize(r.perms...) {
+		ctx.Throw(http.StatusForbidden, "forbidden")
+	}
+}
+
+// RouteGroup registers routes with same prefix, handlers and permissions.
+type RouteGroup struct {
+	router *Router
+	prefix string
+	fn     []Handler
+	perms  []string
+}
+
+// Group returns route group with prefix and shared handlers.
+// Shared handlers are invoked before permission check and route handlers.
+func (rt *Router) Group(prefix string, fn ...Handler) *RouteGroup {
+	return &RouteGroup{router: rt, prefix: prefix, fn: fn}
+}
+
+// Group returns sub group inheriting prefix, handlers and permissions.
+func (g *RouteGroup) Group(prefix string, fn ...Handler) *RouteGroup {
+	sub := g.router.Group(g.prefix+prefix, append(append([]Handler{}, g.fn...), fn...)...)
+	sub.perms = append([]string{}, g.perms...)
+	return sub
+}
+
+// Require adds permissions to routes registered in this group afterwards.
+func (g *RouteGroup) Require(perms ...string) *RouteGroup {
+	g.perms = append(g.perms, perms...)
+	return g
+}
+
+func (g *RouteGroup) add(method string, pattern string, fn []Handler) *Route {
+	handlers := append(append([]Handler{}, g.fn...), fn...)
+	r := g.router.add(method, g.prefix+pattern, handlers)
+	r.shared = len(g.fn)
+	return r.Require(g.perms...)
+}
+
+// Get registers GET handlers in group.
+func (g *RouteGroup) Get(pattern string, fn ...Handler) *Route {
+	return g.add(ROUTER_METHOD_GET, pattern, fn)
+}
+
+// Post registers POST handlers in group.
+func (g *RouteGroup) Post(pattern string, fn ...Handler) *Route {
+	return g.add(ROUTER_METHOD_POST, pattern, fn)
+}
+
+// Put registers PUT handlers in group.
+func (g *RouteGroup) Put(pattern string, fn ...Handler) *Route {
+	return g.add(ROUTER_METHOD_PUT, pattern, fn)
+}
+
+// Delete registers DELETE handlers in group.
+func (g *RouteGroup) Delete(pattern string, fn ...Handler) *Route {
+	return g.add(ROUTER_METHOD_DELETE, pattern, fn)
+}
+
+// Options registers OPTIONS handlers in group.
+func (g *RouteGroup) Options(pattern string, fn ...Handler) *Route {
+	return g.add(ROUTER_METHOD_OPTIONS, pattern, fn)
+}
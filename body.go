@@ -0,0 +1,128 @@
+package GoInk
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	goUrl "net/url"
+	"strings"
+)
+
+// ParseForm parses request body once with size limit, and returns parse error.
+// It's called by input accessors lazily, so the limit can be changed by handlers before first input access.
+// The limit is BodyLimit value, or Route.BodyLimit of matched route, or config app.upload_max_size
+// for multipart form, 32MB by default, or config app.body_max_size, 10MB by default.
+// Negative value means no limit.
+// Multipart form keeps config app.upload_memory bytes in memory, 32MB by default.
+// If body is too large, it throws 413. If body is malformed, it throws 400.
+// Url query values are parsed even body is invalid.
+func (ctx *Context) ParseForm() error {
+	if ctx.parsed {
+		return ctx.formError
+	}
+	ctx.parsed = true
+	ctx.formError = ctx.parseBody()
+	if ctx.formError != nil && !ctx.IsSend {
+		ctx.throwFormError(ctx.formError)
+	}
+	return ctx.formError
+}
+
+func (ctx *Context) parseBody() error {
+	isMultipart := strings.HasPrefix(ctx.GetHeader("Content-Type"), "multipart/form-data")
+	if max := ctx.bodyLimit(isMultipart); max > 0 && ctx.Request.Body != nil {
+		if ctx.Request.ContentLength > max {
+			ctx.Request.Form = ctx.Request.URL.Query()
+			ctx.Request.PostForm = make(goUrl.Values)
+			return &http.MaxBytesError{Limit: max}
+		}
+		ctx.Request.Body = http.MaxBytesReader(ctx.Response, ctx.Request.Body, max)
+	}
+	if ctx.Request.Body != nil {
+		ctx.Request.Body = &bodyCounter{ReadCloser: ctx.Request.Body, n: &ctx.bodyRead}
+	}
+	if !isMultipart {
+		return ctx.Request.ParseForm()
+	}
+	memory := int64(ctx.app.config.Int("app.upload_memory"))
+	if memory <= 0 {
+		memory = 32 << 20
+	}
+	return ctx.Request.ParseMultipartForm(memory)
+}
+
+func (ctx *Context) bodyLimit(isMultipart bool) int64 {
+	if ctx.bodyMax != 0 {
+		return ctx.bodyMax
+	}
+	if ctx.route != nil && ctx.route.bodyMax != 0 {
+		return ctx.route.bodyMax
+	}
+	key, max := "app.body_max_size", int64(10<<20)
+	if isMultipart {
+		key, max = "app.upload_max_size", 32<<20
+	}
+	if ctx.app.config.String(key) != "" {
+		return int64(ctx.app.config.Int(key))
+	}
+	return max
+}
+
+// bodyCounter counts bytes read from request body.
+type bodyCounter struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (bc *bodyCounter) Read(p []byte) (int, error) {
+	n, e := bc.ReadCloser.Read(p)
+	*bc.n += int64(n)
+	return n, e
+}
+
+// FormError returns error of parsing request body.
+// It's nil if body is valid or not parsed yet.
+func (ctx *Context) FormError() error {
+	return ctx.formError
+}
+
+// throwFormError throws 413 if body is too large, or 400 if body is malformed.
+// The parse error is passed to the status event.
+func (ctx *Context) throwFormError(e error) {
+	var maxError *http.MaxBytesError
+	if errors.As(e, &maxError) {
+		ctx.Throw(http.StatusRequestEntityTooLarge, e)
+		return
+	}
+	ctx.Throw(http.StatusBadRequest, e)
+}
+
+// BodyLimit returns handler that limits request body size to max bytes and parses it.
+// Negative max means no limit. Zero max means using config limits.
+// If body is too large, it throws 413. If body is malformed, it throws 400.
+// If body is parsed by middleware before, such as Csrf middleware, the read size is checked again with max,
+// but it can't raise the limit of middleware parsing. Use Route.BodyLimit for that.
+// Usage:
+//
+//	app.Post("/upload", GoInk.BodyLimit(10<<20), uploadHandler)
+func BodyLimit(max int64) Handler {
+	return func(ctx *Context) {
+		if max != 0 {
+			ctx.bodyMax = max
+		}
+		if ctx.parsed && max > 0 && (ctx.Request.ContentLength > max || ctx.bodyRead > max) {
+			ctx.Throw(http.StatusRequestEntityTooLarge, &http.MaxBytesError{Limit: max})
+			return
+		}
+		ctx.ParseForm()
+	}
+}
+
+// BodyLimit sets request body size limit of route, used by all input parsing of the request,
+// including parsing by middleware. Negative max means no limit.
+//
+//	app.Router().Post("/upload/", uploadHandler).BodyLimit(100 << 20)
+func (r *Route) BodyLimit(max int64) *Route {
+	r.bodyMax = max
+	return r
+}
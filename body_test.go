@@ -0,0 +1,61 @@
+package GoInk
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyLimit(t *testing.T) {
+	app := New()
+	app.Config().Set("app.body_max_size", 100)
+	// middleware reading input parses body before route handlers
+	app.Use(func(ctx *Context) {
+		ctx.String("token")
+	})
+	var query string
+	handler := func(ctx *Context) {
+		query = ctx.String("q")
+		ctx.Body = []byte("ok")
+	}
+	app.Post("/form/", handler)
+	app.Post("/small/", BodyLimit(10), handler)
+	app.Router().Post("/large/", handler).BodyLimit(1000)
+
+	tests := []struct {
+		name   string
+		url    string
+		body   string
+		status int
+		query  string
+	}{
+		// handlers after failed parsing are not invoked
+		{"in app limit", "/form/?q=1", "a=" + strings.Repeat("x", 50), 200, "1"},
+		{"over app limit", "/form/?q=1", "a=" + strings.Repeat("x", 200), 413, ""},
+		{"malformed", "/form/?q=1", "a=%zz", 400, ""},
+		{"over handler limit", "/small/?q=1", "a=" + strings.Repeat("x", 50), 413, ""},
+		{"in route limit", "/large/?q=1", "a=" + strings.Repeat("x", 500), 200, "1"},
+		{"over route limit", "/large/?q=1", "a=" + strings.Repeat("x", 2000), 413, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query = ""
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if query != tt.query {
+				t.Fatalf("query q = %q, want %q", query, tt.query)
+			}
+		})
+	}
+
+	// query is kept if body is too large
+	ctx := NewContext(app, httptest.NewRecorder(), httptest.NewRequest("POST", "/?q=1", strings.NewReader(strings.Repeat("x", 200))))
+	if ctx.String("q") != "1" || ctx.FormError() == nil || ctx.Status != 413 {
+		t.Fatalf("q = %q, error = %v, status = %d", ctx.String("q"), ctx.FormError(), ctx.Status)
+	}
+}
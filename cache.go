@@ -0,0 +1,390 @@
+package GoInk
+
+import (
+	"container/list"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedResponse is saved response of response cache.
+type CachedResponse struct {
+	Status int
+	Header map[string]string
+	Body   []byte
+	Tags   []string
+	Expire time.Time
+	// Public response by Cache-Control public or s-maxage is served to requests with credentials too
+	Public bool
+}
+
+// CacheStore saves cached responses.
+type CacheStore interface {
+	// Get returns response of key if not expired.
+	Get(key string) (*CachedResponse, bool)
+	// Set saves response of key with ttl.
+	Set(key string, resp *CachedResponse, ttl time.Duration)
+	// Delete removes response of key.
+	Delete(key string)
+	// DeleteTag removes all responses with tag.
+	DeleteTag(tag string)
+}
+
+type memoryCacheItem struct {
+	key  string
+	resp *CachedResponse
+}
+
+// MemoryCacheStore is LRU cache store in memory.
+type MemoryCacheStore struct {
+	max   int
+	list  *list.List
+	items map[string]*list.Element
+	tags  map[string]map[string]bool
+	lock  sync.Mutex
+}
+
+// NewMemoryCacheStore returns LRU store keeping max responses at most.
+func NewMemoryCacheStore(max int) *MemoryCacheStore {
+	return &MemoryCacheStore{
+		max:   max,
+		list:  list.New(),
+		items: make(map[string]*list.Element),
+		tags:  make(map[string]map[string]bool),
+	}
+}
+
+// Get returns response of key and marks it recently used.
+func (ms *MemoryCacheStore) Get(key string) (*CachedResponse, bool) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	el, ok := ms.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := el.Value.(*memoryCacheItem)
+	if time.Now().After(item.resp.Expire) {
+		ms.remove(el)
+		return nil, false
+	}
+	ms.list.MoveToFront(el)
+	return item.resp, true
+}
+
+// Set saves response and removes least recently used one if full.
+func (ms *MemoryCacheStore) Set(key string, resp *CachedResponse, ttl time.Duration) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	resp.Expire = time.Now().Add(ttl)
+	if el, ok := ms.items[key]; ok {
+		ms.remove(el)
+	}
+	ms.items[key] = ms.list.PushFront(&memoryCacheItem{key, resp})
+	for _, tag := range resp.Tags {
+		if ms.tags[tag] == nil {
+			ms.tags[tag] = make(map[string]bool)
+		}
+		ms.tags[tag][key] = true
+	}
+	for ms.max > 0 && ms.list.Len() > ms.max {
+		ms.remove(ms.list.Back())
+	}
+}
+
+func (ms *MemoryCacheStore) remove(el *list.Element) {
+	item := el.Value.(*memoryCacheItem)
+	ms.list.Remove(el)
+	delete(ms.items, item.key)
+	for _, tag := range item.resp.Tags {
+		delete(ms.tags[tag], item.key)
+		if len(ms.tags[tag]) < 1 {
+			delete(ms.tags, tag)
+		}
+	}
+}
+
+// Delete removes response of key.
+func (ms *MemoryCacheStore) Delete(key string) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if el, ok := ms.items[key]; ok {
+		ms.remove(el)
+	}
+}
+
+// DeleteTag removes all responses with tag.
+func (ms *MemoryCacheStore) DeleteTag(tag string) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	for key := range ms.tags[tag] {
+		if el, ok := ms.items[key]; ok {
+			ms.remove(el)
+		}
+	}
+}
+
+// CacheTag adds tags to response of this context, so it can be purged by ResponseCache.PurgeTag.
+func (ctx *Context) CacheTag(tags ...string) {
+	ctx.cacheTags = append(ctx.cacheTags, tags...)
+}
+
+// ResponseCache caches GET responses by method, path, query and vary headers.
+type ResponseCache struct {
+	Store CacheStore
+	// Request headers in cache key, such as "Accept-Language"
+	Headers []string
+	// Default ttl
+	TTL time.Duration
+
+	calls  map[string]*sync.WaitGroup
+	varies map[string][]string
+	lock   sync.Mutex
+}
+
+// cacheSkipHeaders are per-request response headers never saved in cache.
+var cacheSkipHeaders = []string{"Set-Cookie", "X-Cache", "X-Request-Id", "Retry-After", "X-Ratelimit-", "Access-Control-"}
+
+// NewResponseCache returns response cache with store, default ttl and vary headers.
+func NewResponseCache(store CacheStore, ttl time.Duration, headers ...string) *ResponseCache {
+	return &ResponseCache{
+		Store:   store,
+		TTL:     ttl,
+		Headers: headers,
+		calls:   make(map[string]*sync.WaitGroup),
+		varies:  make(map[string][]string),
+	}
+}
+
+// Key returns cache key of request.
+// Responses with Vary header are saved by the key and varied request headers.
+func (rc *ResponseCache) Key(req *http.Request) string {
+	key := "GET " + req.URL.Path
+	if query := req.URL.Query(); len(query) > 0 {
+		// Encode sorts by key, so param order doesn't matter
+		key += "?" + query.Encode()
+	}
+	return key + cacheVaryKey(req, rc.Headers)
+}
+
+// cacheVaryKey returns request header values of names, sorted by name.
+func cacheVaryKey(req *http.Request, names []string) string {
+	names = append([]string{}, names...)
+	sort.Strings(names)
+	var key strings.Builder
+	for _, h := range names {
+		key.WriteString("\n" + http.CanonicalHeaderKey(h) + ": " + req.Header.Get(h))
+	}
+	return key.String()
+}
+
+// storeKey returns key in store of request, with headers varied by last saved response of key.
+func (rc *ResponseCache) storeKey(req *http.Request, key string) string {
+	rc.lock.Lock()
+	vary := rc.varies[key]
+	rc.lock.Unlock()
+	if len(vary) < 1 {
+		return key
+	}
+	return key + "\nVary" + cacheVaryKey(req, vary)
+}
+
+// Purge removes cached responses of key, including all varied ones.
+func (rc *ResponseCache) Purge(key string) {
+	rc.Store.Delete(key)
+	rc.Store.DeleteTag(cacheVaryTag(key))
+}
+
+// cacheVaryTag is internal tag of varied responses of key.
+func cacheVaryTag(key string) string {
+	return "\x00vary\n" + key
+}
+
+// PurgeTag removes cached responses with tag.
+func (rc *ResponseCache) PurgeTag(tag string) {
+	rc.Store.DeleteTag(tag)
+}
+
+// Handler returns handler serving cached response, or caching response of next handlers.
+// Optional ttl overrides default ttl for per-route cache:
+//
+//	app.Get("/post/:id/", cache.Handler(10*time.Minute), postHandler)
+//
+// Concurrent GET misses of same key wait for the first one instead of rendering again,
+// and render by themselves if the first response is not cached.
+// Cache-Control no-store, no-cache or private from handlers skips caching, max-age or s-maxage changes ttl.
+// Response Vary headers are added to key, and Vary "*" skips caching.
+// Headers set by handlers before it, cookies, request id, rate limit and cors headers are not saved.
+// Requests with Authorization header, principal or session, and responses setting cookies
+// are only served and saved with Cache-Control public or s-maxage, as shared cache of RFC 9111.
+// Responses of context with csrf token or csp nonce are not saved, as body holds per-request values.
+func (rc *ResponseCache) Handler(ttl ...time.Duration) Handler {
+	defTTL := rc.TTL
+	if len(ttl) > 0 {
+		defTTL = ttl[0]
+	}
+	return func(ctx *Context) {
+		if ctx.Method != "GET" && ctx.Method != "HEAD" {
+			return
+		}
+		key := rc.Key(ctx.Request)
+		private := cachePrivate(ctx)
+		if resp, ok := rc.Store.Get(rc.storeKey(ctx.Request, key)); ok && (resp.Public || !private) {
+			rc.serve(ctx, resp)
+			return
+		}
+		// HEAD response is never saved and private response is rarely saved,
+		// so they don't lead or wait others
+		var wg *sync.WaitGroup
+		if ctx.Method == "GET" && !private {
+			rc.lock.Lock()
+			leader, ok := rc.calls[key]
+			if !ok {
+				wg = new(sync.WaitGroup)
+				wg.Add(1)
+				rc.calls[key] = wg
+			}
+			rc.lock.Unlock()
+			if ok {
+				leader.Wait()
+				if resp, ok := rc.Store.Get(rc.storeKey(ctx.Request, key)); ok {
+					rc.serve(ctx, resp)
+					return
+				}
+				// the first one didn't cache response, so render without waiting again
+			}
+		}
+		release := func() {
+			if wg == nil {
+				return
+			}
+			rc.lock.Lock()
+			if rc.calls[key] == wg {
+				delete(rc.calls, key)
+			}
+			rc.lock.Unlock()
+			wg.Done()
+			wg = nil
+		}
+		// headers set by previous handlers are made for this request, such as request id
+		before := make(map[string]string, len(ctx.Header))
+		for k, v := range ctx.Header {
+			before[k] = v
+		}
+		ctx.On(CONTEXT_BEFORE_SEND, func() {
+			rc.save(ctx, key, defTTL, before)
+			// waiters are released when response is saved or not cacheable
+			release()
+		})
+		ctx.On(CONTEXT_END, release)
+	}
+}
+
+func (rc *ResponseCache) serve(ctx *Context, resp *CachedResponse) {
+	for k, v := range resp.Header {
+		if k != "Vary" {
+			ctx.Header[k] = v
+			continue
+		}
+		for _, name := range strings.Split(v, ",") {
+			addVary(ctx, strings.TrimSpace(name))
+		}
+	}
+	ctx.Header["X-Cache"] = "HIT"
+	ctx.Status = resp.Status
+	ctx.Body = resp.Body
+	ctx.End()
+}
+
+func (rc *ResponseCache) save(ctx *Context, key string, ttl time.Duration, before map[string]string) {
+	// fresh context sends 304 without body, it's not the response for others
+	if ctx.Status != http.StatusOK || ctx.Method != "GET" || ctx.isFresh || len(ctx.Body) < 1 {
+		return
+	}
+	// body holds token and nonce of this request, others can't use them
+	if ctx.csrfToken != "" || ctx.cspNonce != "" {
+		return
+	}
+	public := false
+	for _, directive := range strings.Split(strings.ToLower(ctx.Header["Cache-Control"]), ",") {
+		directive = strings.TrimSpace(directive)
+		switch {
+		case directive == "no-store" || directive == "no-cache" || directive == "private":
+			return
+		case directive == "public":
+			public = true
+		case strings.HasPrefix(directive, "s-maxage="):
+			public = true
+			if sec, e := strconv.Atoi(directive[9:]); e == nil {
+				ttl = time.Duration(sec) * time.Second
+			}
+		case strings.HasPrefix(directive, "max-age=") && !strings.Contains(ctx.Header["Cache-Control"], "s-maxage"):
+			if sec, e := strconv.Atoi(directive[8:]); e == nil {
+				ttl = time.Duration(sec) * time.Second
+			}
+		}
+	}
+	if ttl <= 0 || (!public && (cachePrivate(ctx) || cacheSetsCookie(ctx))) {
+		return
+	}
+	vary := make([]string, 0)
+	for _, name := range strings.Split(ctx.Header["Vary"], ",") {
+		if name = strings.TrimSpace(name); name == "*" {
+			return
+		} else if name != "" {
+			vary = append(vary, name)
+		}
+	}
+	header := make(map[string]string, len(ctx.Header))
+	for k, v := range ctx.Header {
+		if old, ok := before[k]; (ok && old == v && k != "Vary") || cacheSkipHeader(k) {
+			continue
+		}
+		header[k] = v
+	}
+	tags := ctx.cacheTags
+	storeKey := key
+	if len(vary) > 0 {
+		tags = append(append([]string{}, tags...), cacheVaryTag(key))
+		storeKey = key + "\nVary" + cacheVaryKey(ctx.Request, vary)
+	}
+	rc.lock.Lock()
+	if len(vary) > 0 {
+		rc.varies[key] = vary
+	} else {
+		delete(rc.varies, key)
+	}
+	rc.lock.Unlock()
+	rc.Store.Set(storeKey, &CachedResponse{
+		Status: ctx.Status,
+		Header: header,
+		Body:   append([]byte{}, ctx.Body...),
+		Tags:   tags,
+		Public: public,
+	}, ttl)
+}
+
+// cachePrivate reports whether request is made with credentials,
+// as Authorization header, authenticated principal or existing session.
+func cachePrivate(ctx *Context) bool {
+	return ctx.GetHeader("Authorization") != "" || ctx.principal != nil || (ctx.session != nil && !ctx.session.isNew)
+}
+
+// cacheSetsCookie reports whether response sets cookies, including session changes not saved yet.
+func cacheSetsCookie(ctx *Context) bool {
+	if ctx.Header["Set-Cookie"] != "" || len(ctx.Response.Header().Values("Set-Cookie")) > 0 {
+		return true
+	}
+	return ctx.session != nil && (ctx.session.isChanged || ctx.session.isDestroy)
+}
+
+func cacheSkipHeader(name string) bool {
+	for _, skip := range cacheSkipHeaders {
+		if strings.EqualFold(name, skip) || (strings.HasSuffix(skip, "-") && len(name) > len(skip) && strings.EqualFold(name[:len(skip)], skip)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package GoInk
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	app := New()
+	rc := NewResponseCache(NewMemoryCacheStore(100), time.Minute)
+	accounts := BasicAuth("test", BasicAccounts(map[string]string{"alice": "a", "bob": "b"}))
+	hello := func(ctx *Context) {
+		ctx.Body = []byte("hello " + ctx.String("name"))
+	}
+	app.Get("/page/", rc.Handler(), hello)
+	app.Get("/me/", accounts, rc.Handler(), func(ctx *Context) {
+		ctx.Body = []byte("hello " + ctx.principal.(string))
+	})
+	app.Get("/shared/", accounts, rc.Handler(), func(ctx *Context) {
+		ctx.Header["Cache-Control"] = "public, max-age=60"
+		ctx.Body = []byte("shared for " + ctx.principal.(string))
+	})
+	app.Get("/cookie/", rc.Handler(), func(ctx *Context) {
+		ctx.SetCookie("visit", ctx.String("name"), nil)
+		ctx.Body = []byte("hello " + ctx.String("name"))
+	})
+	app.Get("/private/", rc.Handler(), func(ctx *Context) {
+		ctx.Header["Cache-Control"] = "private"
+		ctx.Body = []byte("hello " + ctx.String("name"))
+	})
+	csp := app.Group("/csp", SecureHeaders(&SecureOption{ContentSecurityPolicy: "script-src {nonce}"}), rc.Handler())
+	csp.Get("/", func(ctx *Context) {
+		ctx.Body = []byte(`<script nonce="` + ctx.CspNonce() + `"></script>`)
+	})
+	form := app.Group("/form", Csrf(app, nil), rc.Handler())
+	form.Get("/", func(ctx *Context) {
+		ctx.Body = []byte(`<input name="_csrf" value="` + ctx.CsrfToken() + `"/>`)
+	})
+
+	type request struct {
+		url   string
+		user  string
+		cache string
+		body  string
+	}
+	tests := []struct {
+		name     string
+		requests []request
+	}{
+		{"hit", []request{
+			{"/page/?name=a", "", "", "hello a"},
+			{"/page/?name=a", "", "HIT", "hello a"},
+			{"/page/?name=b", "", "", "hello b"},
+		}},
+		{"authorization is not shared", []request{
+			{"/me/", "alice", "", "hello alice"},
+			{"/me/", "bob", "", "hello bob"},
+			{"/me/", "alice", "", "hello alice"},
+		}},
+		{"public response is shared", []request{
+			{"/shared/", "alice", "", "shared for alice"},
+			{"/shared/", "bob", "HIT", "shared for alice"},
+		}},
+		{"response setting cookie", []request{
+			{"/cookie/?name=a", "", "", "hello a"},
+			{"/cookie/?name=a", "", "", "hello a"},
+		}},
+		{"private response", []request{
+			{"/private/?name=a", "", "", "hello a"},
+			{"/private/?name=a", "", "", "hello a"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, r := range tt.requests {
+				req := httptest.NewRequest("GET", r.url, nil)
+				if r.user != "" {
+					req.SetBasicAuth(r.user, r.user[:1])
+				}
+				w := httptest.NewRecorder()
+				app.ServeHTTP(w, req)
+				if w.Code != 200 || w.Body.String() != r.body || w.Header().Get("X-Cache") != r.cache {
+					t.Fatalf("request %d = %d %q X-Cache %q, want 200 %q X-Cache %q",
+						i, w.Code, w.Body.String(), w.Header().Get("X-Cache"), r.body, r.cache)
+				}
+			}
+		})
+	}
+
+	// per-request nonce and token in body are not replayed
+	for _, url := range []string{"/csp/", "/form/"} {
+		first, second := httptest.NewRecorder(), httptest.NewRecorder()
+		app.ServeHTTP(first, httptest.NewRequest("GET", url, nil))
+		app.ServeHTTP(second, httptest.NewRequest("GET", url, nil))
+		if second.Header().Get("X-Cache") != "" || first.Body.String() == second.Body.String() {
+			t.Fatalf("%s body is cached: %q", url, second.Body.String())
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package GoInk
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// CompressOption defines response compression settings.
+// Brotli is not supported, as standard library has no encoder,
+// but precompressed .br files are served by StaticHandler.
+type CompressOption struct {
+	// Compression level, gzip.DefaultCompression by default
+	Level int
+	// Minimum body bytes to compress, 1024 by default
+	MinSize int
+	// Compressible content types, item ends with "/" matches prefix
+	Types []string
+}
+
+// NewCompressOption returns compression option from config:
+//
+//	compress.level     -1 (default compression) by default
+//	compress.min_size  1024 by default
+//	compress.types     text, json, javascript, xml and svg by default, separated by comma
+func NewCompressOption(cfg *Config) *CompressOption {
+	opt := &CompressOption{
+		Level:   gzip.DefaultCompression,
+		MinSize: cfg.Int("compress.min_size"),
+		Types:   splitConfig(cfg.String("compress.types")),
+	}
+	if cfg.String("compress.level") != "" {
+		opt.Level = cfg.Int("compress.level")
+	}
+	if opt.MinSize <= 0 {
+		opt.MinSize = 1024
+	}
+	if len(opt.Types) < 1 {
+		opt.Types = []string{"text/", "application/json", "application/javascript",
+			"application/xml", "application/rss+xml", "image/svg+xml"}
+	}
+	return opt
+}
+
+func (opt *CompressOption) allowType(contentType string) bool {
+	contentType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	if contentType == "" {
+		return false
+	}
+	for _, t := range opt.Types {
+		if contentType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(contentType, t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compress returns middleware handler compressing response by gzip or deflate negotiated from Accept-Encoding.
+// Nil option loads settings by NewCompressOption from app config.
+// It wraps Context.Response, so both Body and streaming writes are compressed.
+// Body less than MinSize, partial content and already encoded responses are sent as they are.
+func Compress(opt *CompressOption) Handler {
+	var (
+		once sync.Once
+		pool sync.Pool
+	)
+	return func(ctx *Context) {
+		once.Do(func() {
+			if opt == nil {
+				opt = NewCompressOption(ctx.app.config)
+			}
+		})
+		if ctx.Method == "HEAD" {
+			return
+		}
+		accept := ctx.GetHeader("Accept-Encoding")
+		encoding := ""
+		if acceptEncoding(accept, "gzip") {
+			encoding = "gzip"
+		} else if acceptEncoding(accept, "deflate") {
+			encoding = "deflate"
+		}
+		// Context.End closes it
+		ctx.Response = &compressWriter{ResponseWriter: ctx.Response, opt: opt, encoding: encoding, pool: &pool}
+	}
+}
+
+// compressWriter buffers first MinSize bytes to decide compression, then writes through encoder.
+type compressWriter struct {
+	http.ResponseWriter
+	opt      *CompressOption
+	encoding string
+	pool     *sync.Pool
+	status   int
+	buf      []byte
+	encoder  io.WriteCloser
+	decided  bool
+	closed   bool
+}
+
+// WriteHeader keeps status until compression is decided.
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.status != 0 {
+		return
+	}
+	cw.status = status
+	header := cw.Header()
+	if cw.opt.allowType(header.Get("Content-Type")) {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if !cw.eligible() {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) eligible() bool {
+	header := cw.Header()
+	if cw.encoding == "" || cw.status < 200 || cw.status == 204 || cw.status == 206 || cw.status == 304 {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	return cw.opt.allowType(header.Get("Content-Type"))
+}
+
+// decide writes header with or without compression, then flushes buffer.
+func (cw *compressWriter) decide(compress bool) error {
+	cw.decided = true
+	if compress {
+		header := cw.Header()
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+		header.Del("Accept-Ranges")
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			// encoded body is different bytes, strong etag becomes weak
+			header.Set("ETag", "W/"+etag)
+		}
+		cw.encoder = cw.newEncoder()
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if len(cw.buf) < 1 {
+		return nil
+	}
+	buf := cw.buf
+	cw.buf = nil
+	_, e := cw.write(buf)
+	return e
+}
+
+func (cw *compressWriter) newEncoder() io.WriteCloser {
+	if cw.encoding == "deflate" {
+		w, e := zlib.NewWriterLevel(cw.ResponseWriter, cw.opt.Level)
+		if e != nil {
+			w = zlib.NewWriter(cw.ResponseWriter)
+		}
+		return w
+	}
+	if w, ok := cw.pool.Get().(*gzip.Writer); ok {
+		w.Reset(cw.ResponseWriter)
+		return w
+	}
+	w, e := gzip.NewWriterLevel(cw.ResponseWriter, cw.opt.Level)
+	if e != nil {
+		w = gzip.NewWriter(cw.ResponseWriter)
+	}
+	return w
+}
+
+func (cw *compressWriter) write(p []byte) (int, error) {
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Write buffers data until MinSize, then starts compression.
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		return cw.write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.opt.MinSize {
+		if e := cw.decide(true); e != nil {
+			return 0, e
+		}
+	}
+	return len(p), nil
+}
+
+// Flush starts compression of buffered data for streaming response, and flushes it to client.
+func (cw *compressWriter) Flush() {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		cw.decide(true)
+	}
+	if f, ok := cw.encoder.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close sends buffered data uncompressed if less than MinSize, or finishes compression.
+func (cw *compressWriter) Close() error {
+	if cw.closed {
+		return nil
+	}
+	cw.closed = true
+	if cw.status == 0 {
+		return nil
+	}
+	if !cw.decided {
+		return cw.decide(false)
+	}
+	if cw.encoder == nil {
+		return nil
+	}
+	e := cw.encoder.Close()
+	if gw, ok := cw.encoder.(*gzip.Writer); ok {
+		cw.pool.Put(gw)
+	}
+	return e
+}
+
+// Unwrap returns native response writer for http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
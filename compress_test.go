@@ -0,0 +1,72 @@
+package GoInk
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	app := New()
+	app.Use(Compress(nil))
+	large := strings.Repeat("compress ", 200)
+	app.Get("/large/", func(ctx *Context) {
+		ctx.Body = []byte(large)
+	})
+	app.Get("/small/", func(ctx *Context) {
+		ctx.Body = []byte("small")
+	})
+	app.Get("/image/", func(ctx *Context) {
+		ctx.ContentType("image/png")
+		ctx.Body = []byte(large)
+	})
+
+	tests := []struct {
+		name     string
+		url      string
+		accept   string
+		encoding string
+		body     string
+	}{
+		{"gzip", "/large/", "gzip, deflate", "gzip", large},
+		{"deflate", "/large/", "deflate", "deflate", large},
+		{"not accepted", "/large/", "", "", large},
+		{"rejected by q", "/large/", "gzip;q=0", "", large},
+		{"small body", "/small/", "gzip", "", "small"},
+		{"not compressible type", "/image/", "gzip", "", large},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			req.Header.Set("Accept-Encoding", tt.accept)
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if enc := w.Header().Get("Content-Encoding"); enc != tt.encoding {
+				t.Fatalf("Content-Encoding = %q, want %q", enc, tt.encoding)
+			}
+			var (
+				r io.Reader = w.Body
+				e error
+			)
+			switch tt.encoding {
+			case "gzip":
+				r, e = gzip.NewReader(w.Body)
+			case "deflate":
+				r, e = zlib.NewReader(w.Body)
+			}
+			if e != nil {
+				t.Fatal(e)
+			}
+			b, e := io.ReadAll(r)
+			if e != nil {
+				t.Fatal(e)
+			}
+			if body := string(b); body != tt.body {
+				t.Fatalf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
@@ -3,12 +3,11 @@ package GoInk
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
-	"os"
 	"path"
 	"reflect"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -57,6 +56,21 @@ type Context struct {
 
 	routeParams map[string]string
 	flashData   map[string]interface{}
+	flashNext   map[string]interface{}
+
+	bodyMax   int64
+	bodyRead  int64
+	parsed    bool
+	formError error
+	session   *Session
+	csrfToken string
+	cspNonce  string
+	principal interface{}
+	isFresh   bool
+	cacheTags []string
+	route     *Route
+	logger    Logger
+	requestId string
 
 	eventsFunc map[string][]reflect.Value
 
@@ -65,8 +79,9 @@ type Context struct {
 	// Response is end or not
 	IsEnd bool
 
-	app    *App
-	layout string
+	app      *App
+	layout   string
+	viewData map[string]interface{}
 }
 
 // NewContext creates new context instance by app instance, http request and response.
@@ -74,7 +89,6 @@ func NewContext(app *App, res http.ResponseWriter, req *http.Request) *Context {
 
 	// init context fields
 	context := new(Context)
-	context.flashData = make(map[string]interface{})
 	context.eventsFunc = make(map[string][]reflect.Value)
 	context.app = app
 	context.IsSend = false
@@ -86,10 +100,8 @@ func NewContext(app *App, res http.ResponseWriter, req *http.Request) *Context {
 	context.RequestUrl = req.RequestURI
 	context.Method = req.Method
 	context.Ext = path.Ext(req.URL.Path)
-	context.Host = req.Host
-	context.Ip = strings.Split(req.RemoteAddr, ":")[0]
+	context.Ip, context.Host, context.IsSSL = app.forwardedClient(req, parseHostIp(req.RemoteAddr))
 	context.IsAjax = req.Header.Get("X-Requested-With") == "XMLHttpRequest"
-	context.IsSSL = req.TLS != nil
 	context.Referer = req.Referer()
 	context.UserAgent = req.UserAgent()
 	context.Base = "://" + context.Host + "/"
@@ -105,9 +117,6 @@ func NewContext(app *App, res http.ResponseWriter, req *http.Request) *Context {
 	context.Header = make(map[string]string)
 	context.Header["Content-Type"] = "text/html;charset=UTF-8"
 
-	// parse form automatically
-	req.ParseForm()
-
 	return context
 }
 
@@ -116,20 +125,10 @@ func (ctx *Context) Param(key string) string {
 	return ctx.routeParams[key]
 }
 
-// Flash sets values to this context or gets by key string.
-// The flash items are alive in this context only.
-func (ctx *Context) Flash(key string, v ...interface{}) interface{} {
-	if len(v) > 0 {
-		return ctx.flashData[key]
-	}
-	ctx.flashData[key] = v[0]
-	return nil
-}
-
 // On registers event function to event name string.
 func (ctx *Context) On(e string, fn interface{}) {
 	if reflect.TypeOf(fn).Kind() != reflect.Func {
-		println("only support function type for Context.On method")
+		ctx.Logger().Error("only support function type for Context.On method", "event", e)
 		return
 	}
 	if ctx.eventsFunc[e] == nil {
@@ -154,11 +153,11 @@ func (ctx *Context) Do(e string, args ...interface{}) [][]interface{} {
 	resSlice := make([][]interface{}, 0)
 	for _, fn := range fns {
 		if !fn.IsValid() {
-			println("invalid event function caller for " + e)
+			ctx.Logger().Error("invalid event function caller", "event", e)
 		}
 		numIn := fn.Type().NumIn()
 		if numIn > len(args) {
-			println("not enough parameters for Context.Do(" + e + ")")
+			ctx.Logger().Error("not enough parameters for Context.Do", "event", e)
 			return nil
 		}
 		rArgs := make([]reflect.Value, numIn)
@@ -181,6 +180,7 @@ func (ctx *Context) Do(e string, args ...interface{}) [][]interface{} {
 
 // Input returns all input data map.
 func (ctx *Context) Input() map[string]string {
+	ctx.ParseForm()
 	data := make(map[string]string)
 	for key, v := range ctx.Request.Form {
 		data[key] = v[0]
@@ -190,12 +190,14 @@ func (ctx *Context) Input() map[string]string {
 
 // Strings returns string slice of given key.
 func (ctx *Context) Strings(key string) []string {
+	ctx.ParseForm()
 	return ctx.Request.Form[key]
 }
 
 // String returns input value of given key.
 func (ctx *Context) String(key string) string {
-	return ctx.Request.FormValue(key)
+	ctx.ParseForm()
+	return ctx.Request.Form.Get(key)
 }
 
 // StringOr returns input value of given key instead of def string if empty.
@@ -208,38 +210,41 @@ func (ctx *Context) StringOr(key string, def string) string {
 }
 
 // Int returns input value of given key.
+// It returns 0 if missing or invalid, use Form().Int to get the error.
 func (ctx *Context) Int(key string) int {
 	str := ctx.String(key)
 	i, _ := strconv.Atoi(str)
 	return i
 }
 
-// IntOr returns input value of given key instead of def int if empty.
+// IntOr returns input value of given key instead of def int if missing or invalid.
 func (ctx *Context) IntOr(key string, def int) int {
-	i := ctx.Int(key)
-	if i == 0 {
+	i, e := ctx.Form().Int(key)
+	if e != nil {
 		return def
 	}
 	return i
 }
 
 // Float returns input value of given key.
+// It returns 0 if missing or invalid, use Form().Float to get the error.
 func (ctx *Context) Float(key string) float64 {
 	str := ctx.String(key)
 	f, _ := strconv.ParseFloat(str, 64)
 	return f
 }
 
-// FloatOr returns input value of given key instead of def float if empty.
+// FloatOr returns input value of given key instead of def float if missing or invalid.
 func (ctx *Context) FloatOr(key string, def float64) float64 {
-	f := ctx.Float(key)
-	if f == 0.0 {
+	f, e := ctx.Form().Float(key)
+	if e != nil {
 		return def
 	}
 	return f
 }
 
 // Bool returns input value of given key.
+// It returns false if missing or invalid, use Form().Bool to get the error.
 func (ctx *Context) Bool(key string) bool {
 	str := ctx.String(key)
 	b, _ := strconv.ParseBool(str)
@@ -247,7 +252,8 @@ func (ctx *Context) Bool(key string) bool {
 }
 
 // Cookie gets cookie value by given key when give only string.
-// Cookie sets cookie value by given key, value and expire time string.
+// Cookie sets cookie value by given key, value and expire seconds string.
+// Use SetCookie for more cookie options.
 func (ctx *Context) Cookie(key string, value ...string) string {
 	if len(value) < 1 {
 		c, e := ctx.Request.Cookie(key)
@@ -257,17 +263,10 @@ func (ctx *Context) Cookie(key string, value ...string) string {
 		return c.Value
 	}
 	if len(value) == 2 {
-		t := time.Now()
-		expire, _ := strconv.Atoi(value[1])
-		t = t.Add(time.Duration(expire) * time.Second)
-		cookie := &http.Cookie{
-			Name:    key,
-			Value:   value[0],
-			Path:    "/",
-			MaxAge:  expire,
-			Expires: t,
-		}
-		http.SetCookie(ctx.Response, cookie)
+		opt := ctx.CookieOption()
+		opt.MaxAge, _ = strconv.Atoi(value[1])
+		opt.Expires = time.Now().Add(time.Duration(opt.MaxAge) * time.Second)
+		ctx.SetCookie(key, value[0], opt)
 		return ""
 	}
 	return ""
@@ -305,10 +304,13 @@ func (ctx *Context) Json(data interface{}) {
 
 // Send does response sending.
 // If response is sent, do not sent again.
+// If client cache is fresh by ETag or Last-Modified, send 304 without body.
 func (ctx *Context) Send() {
 	if ctx.IsSend {
 		return
 	}
+	ctx.beforeSend()
+	ctx.checkFresh()
 	for name, value := range ctx.Header {
 		ctx.Response.Header().Set(name, value)
 	}
@@ -318,6 +320,13 @@ func (ctx *Context) Send() {
 	ctx.Do(CONTEXT_SEND)
 }
 
+// beforeSend saves flash items and invokes CONTEXT_BEFORE_SEND event, such as saving session.
+// It's called by Send and file responses, which write response by http.ServeContent.
+func (ctx *Context) beforeSend() {
+	ctx.saveFlash()
+	ctx.Do(CONTEXT_BEFORE_SEND)
+}
+
 // End does end for this context.
 // If context is end, handlers are stopped.
 // If context response is not sent, send response.
@@ -328,6 +337,14 @@ func (ctx *Context) End() {
 	if !ctx.IsSend {
 		ctx.Send()
 	}
+	// finish wrapped response writer, such as compression
+	if c, ok := ctx.Response.(io.Closer); ok {
+		c.Close()
+	}
+	// clean multipart temp files
+	if ctx.Request.MultipartForm != nil {
+		ctx.Request.MultipartForm.RemoveAll()
+	}
 	ctx.IsEnd = true
 	ctx.Do(CONTEXT_END)
 }
@@ -357,10 +374,33 @@ func (ctx *Context) Tpl(tpl string, data map[string]interface{}) string {
 	return string(b)
 }
 
+// Assign sets view data item for this context.
+// The items are added to data of Render if the keys are not set.
+func (ctx *Context) Assign(key string, v interface{}) {
+	if ctx.viewData == nil {
+		ctx.viewData = make(map[string]interface{})
+	}
+	ctx.viewData[key] = v
+}
+
 // Render does template and layout rendering with data.
+// Flash data is assigned as data["Flash"] if not set, as {{.Flash.message}}.
+// Items set by Assign are assigned too.
 // The result bytes are assigned to context.Body.
 // If error, panic.
 func (ctx *Context) Render(tpl string, data map[string]interface{}) {
+	// copy data, so caller's map is not changed
+	view := make(map[string]interface{}, len(data)+len(ctx.viewData)+1)
+	for key, v := range ctx.viewData {
+		view[key] = v
+	}
+	for key, v := range data {
+		view[key] = v
+	}
+	if _, ok := view["Flash"]; !ok {
+		view["Flash"] = ctx.FlashData()
+	}
+	data = view
 	b, e := ctx.app.view.Render(tpl+".html", data)
 	if e != nil {
 		panic(e)
@@ -386,25 +426,3 @@ func (ctx *Context) Func(name string, fn interface{}) {
 func (ctx *Context) App() *App {
 	return ctx.app
 }
-
-// Download sends file download response by file path.
-func (ctx *Context) Download(file string) {
-	f, e := os.Stat(file)
-	if e != nil {
-		ctx.Status = 404
-		return
-	}
-	if f.IsDir() {
-		ctx.Status = 403
-		return
-	}
-	output := ctx.Response.Header()
-	output.Set("Content-Type", "application/octet-stream")
-	output.Set("Content-Disposition", "attachment; filename="+path.Base(file))
-	output.Set("Content-Transfer-Encoding", "binary")
-	output.Set("Expires", "0")
-	output.Set("Cache-Control", "must-revalidate")
-	output.Set("Pragma", "public")
-	http.ServeFile(ctx.Response, ctx.Request, file)
-	ctx.IsSend = true
-}
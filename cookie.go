@@ -0,0 +1,176 @@
+package GoInk
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrCookieSignature means signed or encrypted cookie is tampered or signed by unknown key.
+var ErrCookieSignature = errors.New("cookie signature is invalid")
+
+// CookieOption defines cookie attributes.
+type CookieOption struct {
+	Path     string
+	Domain   string
+	MaxAge   int
+	Expires  time.Time
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+}
+
+// CookieOption returns default cookie option from config:
+//
+//	cookie.path       "/" by default
+//	cookie.domain
+//	cookie.secure
+//	cookie.http_only
+//	cookie.same_site  "lax", "strict" or "none"
+func (ctx *Context) CookieOption() *CookieOption {
+	cfg := ctx.app.config
+	opt := &CookieOption{
+		Path:     cfg.String("cookie.path"),
+		Domain:   cfg.String("cookie.domain"),
+		Secure:   cfg.Bool("cookie.secure"),
+		HttpOnly: cfg.Bool("cookie.http_only"),
+	}
+	if opt.Path == "" {
+		opt.Path = "/"
+	}
+	switch strings.ToLower(cfg.String("cookie.same_site")) {
+	case "lax":
+		opt.SameSite = http.SameSiteLaxMode
+	case "strict":
+		opt.SameSite = http.SameSiteStrictMode
+	case "none":
+		opt.SameSite = http.SameSiteNoneMode
+	}
+	return opt
+}
+
+// SetCookie sets cookie with option. Nil option uses default CookieOption.
+func (ctx *Context) SetCookie(name string, value string, opt *CookieOption) {
+	if opt == nil {
+		opt = ctx.CookieOption()
+	}
+	http.SetCookie(ctx.Response, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     opt.Path,
+		Domain:   opt.Domain,
+		MaxAge:   opt.MaxAge,
+		Expires:  opt.Expires,
+		Secure:   opt.Secure,
+		HttpOnly: opt.HttpOnly,
+		SameSite: opt.SameSite,
+	})
+}
+
+// DeleteCookie deletes cookie by name.
+// The option path and domain should be same as setting.
+func (ctx *Context) DeleteCookie(name string, opt *CookieOption) {
+	if opt == nil {
+		opt = ctx.CookieOption()
+	}
+	del := *opt
+	del.MaxAge = -1
+	del.Expires = time.Unix(0, 0)
+	ctx.SetCookie(name, "", &del)
+}
+
+// cookieSecrets returns secret keys from config cookie.secret, separated by comma.
+// The first key signs or encrypts new cookies, all keys are tried when reading,
+// so old keys can be kept after rotation.
+func (ctx *Context) cookieSecrets() []string {
+	secrets := make([]string, 0)
+	for _, s := range strings.Split(ctx.app.config.String("cookie.secret"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			secrets = append(secrets, s)
+		}
+	}
+	if len(secrets) < 1 {
+		panic("config cookie.secret is empty")
+	}
+	return secrets
+}
+
+func cookieSign(secret string, name string, value string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(name + "|" + value))
+	return mac.Sum(nil)
+}
+
+// signCookieValue returns base64 value and its signature joined by dot.
+func signCookieValue(secret string, name string, value string) string {
+	enc := base64.RawURLEncoding.EncodeToString([]byte(value))
+	return enc + "." + base64.RawURLEncoding.EncodeToString(cookieSign(secret, name, enc))
+}
+
+// verifyCookieValue returns value of signed cookie value if any secret matches signature.
+func verifyCookieValue(secrets []string, name string, signed string) (string, error) {
+	i := strings.LastIndex(signed, ".")
+	if i < 0 {
+		return "", ErrCookieSignature
+	}
+	sign, e := base64.RawURLEncoding.DecodeString(signed[i+1:])
+	if e != nil {
+		return "", ErrCookieSignature
+	}
+	for _, secret := range secrets {
+		if hmac.Equal(sign, cookieSign(secret, name, signed[:i])) {
+			value, e := base64.RawURLEncoding.DecodeString(signed[:i])
+			if e != nil {
+				return "", ErrCookieSignature
+			}
+			return string(value), nil
+		}
+	}
+	return "", ErrCookieSignature
+}
+
+// SetSignedCookie sets cookie with HMAC-SHA256 signature.
+// The value is readable by client but can't be changed.
+func (ctx *Context) SetSignedCookie(name string, value string, opt *CookieOption) {
+	ctx.SetCookie(name, signCookieValue(ctx.cookieSecrets()[0], name, value), opt)
+}
+
+// SignedCookie returns value of signed cookie.
+// It returns http.ErrNoCookie if not found, or ErrCookieSignature if tampered.
+func (ctx *Context) SignedCookie(name string) (string, error) {
+	c, e := ctx.Request.Cookie(name)
+	if e != nil {
+		return "", e
+	}
+	return verifyCookieValue(ctx.cookieSecrets(), name, c.Value)
+}
+
+// SetSecureCookie sets cookie encrypted by AES-GCM.
+// The value can't be read or changed by client.
+func (ctx *Context) SetSecureCookie(name string, value string, opt *CookieOption) {
+	data := encrypt(newAEAD(ctx.cookieSecrets()[0]), []byte(value), []byte(name))
+	ctx.SetCookie(name, base64.RawURLEncoding.EncodeToString(data), opt)
+}
+
+// SecureCookie returns value of encrypted cookie.
+// It returns http.ErrNoCookie if not found, or ErrCookieSignature if tampered.
+func (ctx *Context) SecureCookie(name string) (string, error) {
+	c, e := ctx.Request.Cookie(name)
+	if e != nil {
+		return "", e
+	}
+	data, e := base64.RawURLEncoding.DecodeString(c.Value)
+	if e != nil {
+		return "", ErrCookieSignature
+	}
+	for _, secret := range ctx.cookieSecrets() {
+		if value, e := decrypt(newAEAD(secret), data, []byte(name)); e == nil {
+			return string(value), nil
+		}
+	}
+	return "", ErrCookieSignature
+}
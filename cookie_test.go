@@ -0,0 +1,139 @@
+package GoInk
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// cookieApp returns app with cookie secrets, as config cookie.secret.
+func cookieApp(secret string) *App {
+	app := New()
+	app.Config().Set("cookie.secret", secret)
+	return app
+}
+
+// setCookie returns cookie set by fn in a new context.
+func setCookie(t *testing.T, app *App, fn func(ctx *Context)) *http.Cookie {
+	t.Helper()
+	w := httptest.NewRecorder()
+	fn(NewContext(app, w, httptest.NewRequest("GET", "/", nil)))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("set %d cookies, want 1", len(cookies))
+	}
+	return cookies[0]
+}
+
+// readContext returns context of request with cookies.
+func readContext(app *App, cookies ...*http.Cookie) *Context {
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	return NewContext(app, httptest.NewRecorder(), req)
+}
+
+// flipCookie changes one char of cookie value at index i from end.
+func flipCookie(c *http.Cookie, i int) *http.Cookie {
+	b := []byte(c.Value)
+	j := len(b) - 1 - i
+	if b[j] == 'A' {
+		b[j] = 'B'
+	} else {
+		b[j] = 'A'
+	}
+	return &http.Cookie{Name: c.Name, Value: string(b)}
+}
+
+func TestSignedCookie(t *testing.T) {
+	app := cookieApp("key1")
+	c := setCookie(t, app, func(ctx *Context) {
+		ctx.SetSignedCookie("user", "1", nil)
+	})
+	i := strings.LastIndex(c.Value, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte("2"))
+
+	tests := []struct {
+		name   string
+		app    *App
+		cookie *http.Cookie
+		value  string
+		err    error
+	}{
+		{"valid", app, c, "1", nil},
+		{"missing", app, nil, "", http.ErrNoCookie},
+		{"tampered value", app, &http.Cookie{Name: "user", Value: forged + c.Value[i:]}, "", ErrCookieSignature},
+		{"tampered signature", app, flipCookie(c, 2), "", ErrCookieSignature},
+		{"no signature", app, &http.Cookie{Name: "user", Value: c.Value[:i]}, "", ErrCookieSignature},
+		{"empty signature", app, &http.Cookie{Name: "user", Value: c.Value[:i+1]}, "", ErrCookieSignature},
+		{"unsigned value", app, &http.Cookie{Name: "user", Value: "1"}, "", ErrCookieSignature},
+		{"other secret", cookieApp("key2"), c, "", ErrCookieSignature},
+		{"rotated secret", cookieApp("key2,key1"), c, "1", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := readContext(tt.app)
+			if tt.cookie != nil {
+				ctx = readContext(tt.app, tt.cookie)
+			}
+			value, e := ctx.SignedCookie("user")
+			if e != tt.err || value != tt.value {
+				t.Fatalf("SignedCookie = %q, %v, want %q, %v", value, e, tt.value, tt.err)
+			}
+		})
+	}
+
+	// signature is bound to cookie name
+	moved := readContext(app, &http.Cookie{Name: "admin", Value: c.Value})
+	if _, e := moved.SignedCookie("admin"); e != ErrCookieSignature {
+		t.Fatalf("renamed SignedCookie error = %v, want %v", e, ErrCookieSignature)
+	}
+}
+
+func TestSecureCookie(t *testing.T) {
+	app := cookieApp("key1")
+	c := setCookie(t, app, func(ctx *Context) {
+		ctx.SetSecureCookie("user", "secret-value", nil)
+	})
+	if strings.Contains(c.Value, "secret-value") || strings.Contains(c.Value, base64.RawURLEncoding.EncodeToString([]byte("secret-value"))) {
+		t.Fatalf("secure cookie value is readable: %s", c.Value)
+	}
+
+	tests := []struct {
+		name   string
+		app    *App
+		cookie *http.Cookie
+		value  string
+		err    error
+	}{
+		{"valid", app, c, "secret-value", nil},
+		{"missing", app, nil, "", http.ErrNoCookie},
+		{"tampered tag", app, flipCookie(c, 1), "", ErrCookieSignature},
+		{"tampered ciphertext", app, flipCookie(c, 30), "", ErrCookieSignature},
+		{"truncated", app, &http.Cookie{Name: "user", Value: c.Value[:10]}, "", ErrCookieSignature},
+		{"not base64", app, &http.Cookie{Name: "user", Value: "!!!"}, "", ErrCookieSignature},
+		{"other secret", cookieApp("key2"), c, "", ErrCookieSignature},
+		{"rotated secret", cookieApp("key2,key1"), c, "secret-value", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := readContext(tt.app)
+			if tt.cookie != nil {
+				ctx = readContext(tt.app, tt.cookie)
+			}
+			value, e := ctx.SecureCookie("user")
+			if e != tt.err || value != tt.value {
+				t.Fatalf("SecureCookie = %q, %v, want %q, %v", value, e, tt.value, tt.err)
+			}
+		})
+	}
+
+	// cookie name is additional data, so value can't be moved to other cookie
+	moved := readContext(app, &http.Cookie{Name: "admin", Value: c.Value})
+	if _, e := moved.SecureCookie("admin"); e != ErrCookieSignature {
+		t.Fatalf("renamed SecureCookie error = %v, want %v", e, ErrCookieSignature)
+	}
+}
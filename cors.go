@@ -0,0 +1,162 @@
+package GoInk
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrCorsCredentials means credentials are allowed for origin "*", so any site could send credentialed requests.
+var ErrCorsCredentials = errors.New("cors credentials need explicit origins instead of *")
+
+// CorsOption defines cross-origin resource sharing settings.
+type CorsOption struct {
+	// Allowed origins, as "*", "https://example.com" or "https://*.example.com"
+	Origins []string
+	// Custom func checking origin, used if Origins are not matched
+	OriginFunc func(origin string) bool
+	// Allowed methods, "GET,POST,PUT,DELETE" by default
+	Methods []string
+	// Allowed request headers, reflect request headers if empty
+	Headers []string
+	// Response headers exposed to client
+	ExposeHeaders []string
+	// Allow cookies and authorization, Origins can't be "*" then
+	Credentials bool
+	// Seconds of preflight result cache
+	MaxAge int
+}
+
+// NewCorsOption returns cors option from config, values are separated by comma:
+//
+//	cors.origins         "*" by default, required if cors.credentials is true
+//	cors.methods
+//	cors.headers
+//	cors.expose_headers
+//	cors.credentials
+//	cors.max_age
+func NewCorsOption(cfg *Config) *CorsOption {
+	opt := &CorsOption{
+		Origins:       splitConfig(cfg.String("cors.origins")),
+		Methods:       splitConfig(cfg.String("cors.methods")),
+		Headers:       splitConfig(cfg.String("cors.headers")),
+		ExposeHeaders: splitConfig(cfg.String("cors.expose_headers")),
+		Credentials:   cfg.Bool("cors.credentials"),
+		MaxAge:        cfg.Int("cors.max_age"),
+	}
+	if len(opt.Origins) < 1 {
+		opt.Origins = []string{"*"}
+	}
+	return opt
+}
+
+// splitConfig splits comma-separated config value and trims items.
+func splitConfig(str string) []string {
+	res := make([]string, 0)
+	for _, s := range strings.Split(str, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// check returns ErrCorsCredentials if credentials are allowed for origin "*".
+func (opt *CorsOption) check() error {
+	if !opt.Credentials {
+		return nil
+	}
+	for _, o := range opt.Origins {
+		if o == "*" {
+			return ErrCorsCredentials
+		}
+	}
+	return nil
+}
+
+func (opt *CorsOption) allowOrigin(origin string) bool {
+	for _, o := range opt.Origins {
+		if o == "*" || o == origin {
+			return true
+		}
+		if i := strings.Index(o, "*"); i >= 0 {
+			prefix, suffix := o[:i], o[i+1:]
+			if len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+	}
+	return opt.OriginFunc != nil && opt.OriginFunc(origin)
+}
+
+// Cors returns middleware handler that adds cors headers and answers preflight requests.
+// Nil option loads settings from app config by NewCorsOption.
+// It panics with ErrCorsCredentials if Credentials is allowed for origin "*",
+// so the misconfigured app fails at startup instead of leaking:
+//
+//	app.Use(GoInk.Cors(app, nil))
+func Cors(app *App, opt *CorsOption) Handler {
+	if opt == nil {
+		opt = NewCorsOption(app.config)
+	}
+	if e := opt.check(); e != nil {
+		panic(e)
+	}
+	return func(ctx *Context) {
+		addVary(ctx, "Origin")
+		origin := ctx.GetHeader("Origin")
+		isPreflight := ctx.Method == ROUTER_METHOD_OPTIONS && ctx.GetHeader("Access-Control-Request-Method") != ""
+		if origin == "" || !opt.allowOrigin(origin) {
+			if isPreflight {
+				ctx.Status = 204
+				ctx.End()
+			}
+			return
+		}
+		if len(opt.Origins) == 1 && opt.Origins[0] == "*" {
+			ctx.Header["Access-Control-Allow-Origin"] = "*"
+		} else {
+			ctx.Header["Access-Control-Allow-Origin"] = origin
+		}
+		if opt.Credentials {
+			ctx.Header["Access-Control-Allow-Credentials"] = "true"
+		}
+		if !isPreflight {
+			if len(opt.ExposeHeaders) > 0 {
+				ctx.Header["Access-Control-Expose-Headers"] = strings.Join(opt.ExposeHeaders, ", ")
+			}
+			return
+		}
+		methods := opt.Methods
+		if len(methods) < 1 {
+			methods = []string{ROUTER_METHOD_GET, ROUTER_METHOD_POST, ROUTER_METHOD_PUT, ROUTER_METHOD_DELETE}
+		}
+		ctx.Header["Access-Control-Allow-Methods"] = strings.Join(methods, ", ")
+		if len(opt.Headers) > 0 {
+			ctx.Header["Access-Control-Allow-Headers"] = strings.Join(opt.Headers, ", ")
+		} else if h := ctx.GetHeader("Access-Control-Request-Headers"); h != "" {
+			ctx.Header["Access-Control-Allow-Headers"] = h
+			addVary(ctx, "Access-Control-Request-Headers")
+		}
+		if opt.MaxAge > 0 {
+			ctx.Header["Access-Control-Max-Age"] = strconv.Itoa(opt.MaxAge)
+		}
+		ctx.Status = 204
+		ctx.End()
+	}
+}
+
+// addVary appends header name to Vary response header.
+func addVary(ctx *Context, name string) {
+	vary := ctx.Header["Vary"]
+	for _, v := range strings.Split(vary, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), name) {
+			return
+		}
+	}
+	if vary == "" {
+		ctx.Header["Vary"] = name
+		return
+	}
+	ctx.Header["Vary"] = vary + ", " + name
+}
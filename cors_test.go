@@ -0,0 +1,42 @@
+package GoInk
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCorsCredentials(t *testing.T) {
+	app := New()
+	app.Config().Set("cors.credentials", true)
+	func() {
+		defer func() {
+			if e := recover(); e != ErrCorsCredentials {
+				t.Fatalf("Cors panic = %v, want %v", e, ErrCorsCredentials)
+			}
+		}()
+		Cors(app, nil)
+	}()
+
+	app.Config().Set("cors.origins", "https://a.com, https://*.b.com")
+	app.Use(Cors(app, nil))
+	app.Get("/", func(ctx *Context) {
+		ctx.Body = []byte("ok")
+	})
+	tests := []struct {
+		origin string
+		allow  string
+	}{
+		{"https://a.com", "https://a.com"},
+		{"https://x.b.com", "https://x.b.com"},
+		{"https://evil.com", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Origin", tt.origin)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		if allow := w.Header().Get("Access-Control-Allow-Origin"); allow != tt.allow {
+			t.Fatalf("origin %s allowed as %q, want %q", tt.origin, allow, tt.allow)
+		}
+	}
+}
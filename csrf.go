@@ -0,0 +1,123 @@
+package GoInk
+
+import (
+	"crypto/subtle"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// CsrfOption defines csrf middleware settings.
+type CsrfOption struct {
+	// Form field name, "_csrf" by default
+	Field string
+	// Header name, "X-CSRF-Token" by default
+	Header string
+	// Cookie name of double-submit token, "GOINK_CSRF" by default
+	Cookie string
+	// Save token in session instead of cookie, Sessions middleware should be used before
+	UseSession bool
+	// Url path prefixes skipping check, such as "/webhook/"
+	Exempt []string
+	// Custom func skipping check if returns true
+	ExemptFunc func(ctx *Context) bool
+	// Handler when token is invalid, throw 403 by default
+	Failed Handler
+}
+
+// CsrfToken returns csrf token of this context.
+// It's empty if Csrf middleware is not used.
+func (ctx *Context) CsrfToken() string {
+	return ctx.csrfToken
+}
+
+// Csrf returns middleware handler that checks csrf token on POST, PUT, DELETE and PATCH requests.
+// The token is sent by form field or header, and compared with token in cookie or session.
+// It assigns token as view data "CsrfToken", and registers template function CsrfField to app view
+// when it's created, so templates using it can be parsed before any request:
+//
+//	app.Use(GoInk.Csrf(app, nil))
+//	<form method="post">{{CsrfField .CsrfToken}}</form>
+func Csrf(app *App, opt *CsrfOption) Handler {
+	if opt == nil {
+		opt = new(CsrfOption)
+	}
+	if opt.Field == "" {
+		opt.Field = "_csrf"
+	}
+	if opt.Header == "" {
+		opt.Header = "X-CSRF-Token"
+	}
+	if opt.Cookie == "" {
+		opt.Cookie = "GOINK_CSRF"
+	}
+	if opt.Failed == nil {
+		opt.Failed = func(ctx *Context) {
+			ctx.Throw(http.StatusForbidden, "invalid csrf token")
+		}
+	}
+	app.view.FuncMap["CsrfField"] = func(token string) template.HTML {
+		return template.HTML(`<input type="hidden" name="` + template.HTMLEscapeString(opt.Field) +
+			`" value="` + template.HTMLEscapeString(token) + `"/>`)
+	}
+	return func(ctx *Context) {
+		token := csrfLoadToken(ctx, opt)
+		if token == "" {
+			token = randomString(32)
+			csrfSaveToken(ctx, opt, token)
+		}
+		ctx.csrfToken = token
+		ctx.Assign("CsrfToken", token)
+
+		switch ctx.Method {
+		case "GET", "HEAD", "OPTIONS", "TRACE":
+			return
+		}
+		for _, prefix := range opt.Exempt {
+			if strings.HasPrefix(ctx.Url, prefix) {
+				return
+			}
+		}
+		if opt.ExemptFunc != nil && opt.ExemptFunc(ctx) {
+			return
+		}
+		sent := ctx.GetHeader(opt.Header)
+		if sent == "" {
+			// invalid body is 413 or 400, not invalid token
+			if e := ctx.ParseForm(); e != nil {
+				if !ctx.IsEnd {
+					ctx.throwFormError(e)
+				}
+				return
+			}
+			sent = ctx.String(opt.Field)
+		}
+		if sent == "" || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+			opt.Failed(ctx)
+			if !ctx.IsEnd {
+				ctx.End()
+			}
+		}
+	}
+}
+
+func csrfLoadToken(ctx *Context, opt *CsrfOption) string {
+	if opt.UseSession {
+		token, _ := ctx.Session().Get(opt.Field).(string)
+		return token
+	}
+	return ctx.Cookie(opt.Cookie)
+}
+
+func csrfSaveToken(ctx *Context, opt *CsrfOption, token string) {
+	if opt.UseSession {
+		ctx.Session().Set(opt.Field, token)
+		return
+	}
+	cookie := ctx.CookieOption()
+	cookie.HttpOnly = true
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+	ctx.SetCookie(opt.Cookie, token, cookie)
+}
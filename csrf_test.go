@@ -0,0 +1,62 @@
+package GoInk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCsrf(t *testing.T) {
+	app := New()
+	app.Config().Set("app.body_max_size", 200)
+	app.Use(Csrf(app, nil))
+	app.Route("GET,POST", "/form/", func(ctx *Context) {
+		ctx.Body = []byte("ok")
+	})
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/form/", nil))
+	var cookie *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "GOINK_CSRF" {
+			cookie = c
+		}
+	}
+	if cookie == nil || cookie.Value == "" {
+		t.Fatal("csrf cookie is not set")
+	}
+
+	tests := []struct {
+		name   string
+		cookie bool
+		header string
+		form   string
+		status int
+	}{
+		{"header token", true, cookie.Value, "", 200},
+		{"form token", true, "", "_csrf=" + cookie.Value, 200},
+		{"missing token", true, "", "", 403},
+		{"wrong token", true, strings.Repeat("0", len(cookie.Value)), "", 403},
+		{"token prefix", true, cookie.Value[:len(cookie.Value)-1], "", 403},
+		{"token without cookie", false, cookie.Value, "", 403},
+		{"malformed body", true, "", "_csrf=%zz", 400},
+		{"body too large", true, "", "_csrf=" + cookie.Value + "&a=" + strings.Repeat("x", 200), 413},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/form/", strings.NewReader(tt.form))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.cookie {
+				req.AddCookie(cookie)
+			}
+			if tt.header != "" {
+				req.Header.Set("X-CSRF-Token", tt.header)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
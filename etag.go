@@ -0,0 +1,96 @@
+package GoInk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag sets ETag header and checks If-None-Match request header.
+// It returns true if client cache is fresh, then the handler can skip rendering
+// and 304 is sent without body.
+func (ctx *Context) ETag(tag string, weak bool) bool {
+	tag = `"` + strings.Trim(tag, `"`) + `"`
+	if weak {
+		tag = "W/" + tag
+	}
+	ctx.Header["ETag"] = tag
+	ctx.isFresh = ctx.fresh()
+	return ctx.isFresh
+}
+
+// LastModified sets Last-Modified header and checks If-Modified-Since request header.
+// It returns true if client cache is fresh, then the handler can skip rendering
+// and 304 is sent without body:
+//
+//	if ctx.LastModified(post.Updated) {
+//	    return
+//	}
+func (ctx *Context) LastModified(t time.Time) bool {
+	ctx.Header["Last-Modified"] = t.UTC().Format(http.TimeFormat)
+	ctx.isFresh = ctx.fresh()
+	return ctx.isFresh
+}
+
+// fresh checks conditional request headers with response ETag and Last-Modified.
+// If-None-Match is preferred, If-Modified-Since is used only without it.
+func (ctx *Context) fresh() bool {
+	if ctx.Method != "GET" && ctx.Method != "HEAD" {
+		return false
+	}
+	if inm := ctx.GetHeader("If-None-Match"); inm != "" {
+		etag := strings.TrimPrefix(ctx.Header["ETag"], "W/")
+		if etag == "" {
+			return false
+		}
+		for _, t := range strings.Split(inm, ",") {
+			t = strings.TrimSpace(t)
+			if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	ims, e := http.ParseTime(ctx.GetHeader("If-Modified-Since"))
+	if e != nil {
+		return false
+	}
+	lm, e := http.ParseTime(ctx.Header["Last-Modified"])
+	if e != nil {
+		return false
+	}
+	return !lm.After(ims)
+}
+
+// checkFresh computes body ETag by config app.etag, "strong" or "weak",
+// and changes response to 304 if client cache is fresh.
+func (ctx *Context) checkFresh() {
+	if ctx.Status != http.StatusOK {
+		return
+	}
+	if !ctx.isFresh {
+		if _, ok := ctx.Header["ETag"]; !ok && len(ctx.Body) > 0 {
+			switch ctx.app.config.String("app.etag") {
+			case "strong":
+				ctx.Header["ETag"] = bodyETag(ctx.Body)
+			case "weak":
+				ctx.Header["ETag"] = "W/" + bodyETag(ctx.Body)
+			}
+		}
+		ctx.isFresh = ctx.fresh()
+	}
+	if !ctx.isFresh {
+		return
+	}
+	ctx.Status = http.StatusNotModified
+	ctx.Body = nil
+	delete(ctx.Header, "Content-Type")
+	delete(ctx.Header, "Content-Length")
+}
+
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
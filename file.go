@@ -0,0 +1,179 @@
+package GoInk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileOption defines file response settings.
+type FileOption struct {
+	// File name in Content-Disposition, base name of file by default
+	Name string
+	// Show in browser instead of downloading
+	Inline bool
+	// Content type, detected by name extension or content by default
+	ContentType string
+	// Modified time for Last-Modified and If-Range, file modified time by default
+	ModTime time.Time
+}
+
+// Download sends file download response by file path.
+func (ctx *Context) Download(file string) {
+	ctx.ServeFile(file, nil)
+}
+
+// DownloadFS sends file download response by file name in file system, such as embed.FS.
+func (ctx *Context) DownloadFS(fsys fs.FS, name string) {
+	ctx.ServeFS(fsys, name, nil)
+}
+
+// ServeFile sends file by path with option. Nil option means attachment with file base name.
+// It supports Range and If-Range requests for resumable downloads.
+// It sets status 404 if not found, or 403 if directory.
+func (ctx *Context) ServeFile(file string, opt *FileOption) {
+	ctx.ServeFS(os.DirFS(filepath.Dir(file)), filepath.Base(file), opt)
+}
+
+// ServeFS sends file by name in file system with option, as ServeFile.
+func (ctx *Context) ServeFS(fsys fs.FS, name string, opt *FileOption) {
+	info, e := fs.Stat(fsys, name)
+	if e != nil {
+		ctx.Status = 404
+		return
+	}
+	if info.IsDir() {
+		ctx.Status = 403
+		return
+	}
+	f, e := fsys.Open(name)
+	if e != nil {
+		ctx.Status = 404
+		return
+	}
+	defer f.Close()
+	if opt == nil {
+		opt = new(FileOption)
+	}
+	file := *opt
+	if file.Name == "" {
+		file.Name = path.Base(name)
+	}
+	if file.ModTime.IsZero() {
+		file.ModTime = info.ModTime()
+	}
+	// strong etag, so If-Range works
+	ctx.Response.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, file.ModTime.UnixNano(), info.Size()))
+	ctx.ServeReader(f, &file)
+}
+
+// ServeReader sends content with option. Reader without Seek is read into memory first.
+// Option Name is used for Content-Disposition. Nil option means attachment without file name.
+func (ctx *Context) ServeReader(rd io.Reader, opt *FileOption) {
+	if opt == nil {
+		opt = new(FileOption)
+	}
+	disposition := "attachment"
+	if opt.Inline {
+		disposition = "inline"
+	}
+	ctx.copyHeader()
+	header := ctx.Response.Header()
+	if opt.Name != "" {
+		header.Set("Content-Disposition", ContentDisposition(disposition, opt.Name))
+	} else {
+		header.Set("Content-Disposition", disposition)
+	}
+	ctype := opt.ContentType
+	if ctype == "" {
+		ctype = mime.TypeByExtension(path.Ext(opt.Name))
+	}
+	if ctype != "" {
+		header.Set("Content-Type", ctype)
+	} else if !opt.Inline {
+		header.Set("Content-Type", "application/octet-stream")
+	} else {
+		// let http.ServeContent sniff content
+		header.Del("Content-Type")
+	}
+	ctx.serveContent(opt.Name, opt.ModTime, rd)
+}
+
+// copyHeader runs before send stage, then copies context headers to response except Content-Type.
+// It's called by file responses before setting their own headers.
+func (ctx *Context) copyHeader() {
+	ctx.beforeSend()
+	header := ctx.Response.Header()
+	for k, v := range ctx.Header {
+		if k != "Content-Type" {
+			header.Set(k, v)
+		}
+	}
+}
+
+// serveContent sends content by http.ServeContent and ends context.
+// Reader without Seek is read into memory first.
+func (ctx *Context) serveContent(name string, modtime time.Time, f io.Reader) {
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, e := io.ReadAll(f)
+		if e != nil {
+			panic(e)
+		}
+		rs = bytes.NewReader(data)
+	}
+	// ServeContent writes 206, 304 or 416 by itself, keep it for access log and metrics
+	sw := &statusWriter{ResponseWriter: ctx.Response}
+	http.ServeContent(sw, ctx.Request, name, modtime, rs)
+	if sw.status != 0 {
+		ctx.Status = sw.status
+	}
+	ctx.IsSend = true
+	ctx.End()
+}
+
+// ContentDisposition returns Content-Disposition value with file name encoded by RFC 6266.
+// Non-ascii name gets ascii fallback filename and utf-8 filename* parameter.
+func ContentDisposition(disposition string, name string) string {
+	var ascii, ext strings.Builder
+	isAscii := true
+	for _, r := range name {
+		switch {
+		case r > 0x7e || r < 0x20:
+			isAscii = false
+			ascii.WriteByte('_')
+		case r == '"' || r == '\\':
+			ascii.WriteByte('_')
+		default:
+			ascii.WriteRune(r)
+		}
+	}
+	res := disposition + `; filename="` + ascii.String() + `"`
+	if isAscii {
+		return res
+	}
+	for _, b := range []byte(name) {
+		if isAttrChar(b) {
+			ext.WriteByte(b)
+		} else {
+			fmt.Fprintf(&ext, "%%%02X", b)
+		}
+	}
+	return res + "; filename*=UTF-8''" + ext.String()
+}
+
+// isAttrChar checks RFC 5987 attr-char.
+func isAttrChar(b byte) bool {
+	if b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' {
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", b) >= 0
+}
@@ -0,0 +1,55 @@
+package GoInk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestServeFSStatus(t *testing.T) {
+	app := New()
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	files := fstest.MapFS{"a.txt": {Data: []byte("0123456789"), ModTime: modTime}}
+	app.Get("/file/", func(ctx *Context) {
+		ctx.ServeFS(files, "a.txt", &FileOption{Inline: true})
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		status int
+	}{
+		{"full", "", "", 200},
+		{"range", "Range", "bytes=2-4", 206},
+		{"bad range", "Range", "bytes=20-30", 416},
+		{"not modified", "If-Modified-Since", modTime.Format(http.TimeFormat), 304},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/file/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+
+	// metrics record written status, not Context.Status default
+	var buf strings.Builder
+	app.Metrics().WriteTo(&buf)
+	for _, tt := range tests {
+		series := `goink_http_requests_total{method="GET",route="/file/",status="` + strconv.Itoa(tt.status) + `"} 1`
+		if !strings.Contains(buf.String(), series) {
+			t.Fatalf("metrics miss %s", series)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package GoInk
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Flash gets flash value set in previous request by key string.
+// Flash sets value by key string and value, which is readable once in next request.
+// It's useful for post/redirect/get pattern:
+//
+//	ctx.Flash("message", "saved")
+//	ctx.Redirect("/list")
+//
+// The flash items are saved in cookie named as config app.flash_cookie, "GOINK_FLASH" by default.
+// The cookie is signed by config cookie.secret, so client can't forge flash items.
+// Without the secret, a random key of the process signs it, then flash items are lost
+// after restart or on other app instances.
+func (ctx *Context) Flash(key string, v ...interface{}) interface{} {
+	if len(v) > 0 {
+		if ctx.flashNext == nil {
+			ctx.flashNext = make(map[string]interface{})
+		}
+		ctx.flashNext[key] = v[0]
+		return nil
+	}
+	return ctx.FlashData()[key]
+}
+
+// FlashData returns all flash items set in previous request.
+func (ctx *Context) FlashData() map[string]interface{} {
+	if ctx.flashData != nil {
+		return ctx.flashData
+	}
+	ctx.flashData = make(map[string]interface{})
+	c, e := ctx.Request.Cookie(ctx.flashCookie())
+	if e != nil {
+		return ctx.flashData
+	}
+	value, e := verifyCookieValue(ctx.flashSecrets(), c.Name, c.Value)
+	if e != nil {
+		return ctx.flashData
+	}
+	json.Unmarshal([]byte(value), &ctx.flashData)
+	return ctx.flashData
+}
+
+// flashSecrets returns config cookie.secret keys, or the random key of app if it's empty.
+func (ctx *Context) flashSecrets() []string {
+	if strings.TrimSpace(strings.Replace(ctx.app.config.String("cookie.secret"), ",", "", -1)) != "" {
+		return ctx.cookieSecrets()
+	}
+	return []string{ctx.app.flashKey}
+}
+
+func (ctx *Context) flashCookie() string {
+	name := ctx.app.config.String("app.flash_cookie")
+	if name == "" {
+		return "GOINK_FLASH"
+	}
+	return name
+}
+
+// saveFlash writes next flash items to cookie,
+// or deletes the cookie if previous flash items are read.
+func (ctx *Context) saveFlash() {
+	name := ctx.flashCookie()
+	if len(ctx.flashNext) > 0 {
+		bytes, e := json.Marshal(ctx.flashNext)
+		if e != nil {
+			panic(e)
+		}
+		http.SetCookie(ctx.Response, &http.Cookie{
+			Name:     name,
+			Value:    signCookieValue(ctx.flashSecrets()[0], name, string(bytes)),
+			Path:     "/",
+			HttpOnly: true,
+		})
+		return
+	}
+	if ctx.flashData == nil {
+		return
+	}
+	if _, e := ctx.Request.Cookie(name); e == nil {
+		http.SetCookie(ctx.Response, &http.Cookie{Name: name, Path: "/", MaxAge: -1})
+	}
+}
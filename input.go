@@ -0,0 +1,208 @@
+package GoInk
+
+import (
+	"errors"
+	goUrl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInputMissing means input key is not given.
+var ErrInputMissing = errors.New("input is missing")
+
+// InputError is error of parsing input value by key.
+type InputError struct {
+	Key string
+	Err error
+}
+
+// Error returns error message with input key.
+func (ie *InputError) Error() string {
+	return "input " + ie.Key + ": " + ie.Err.Error()
+}
+
+// Unwrap returns parsing error or ErrInputMissing.
+func (ie *InputError) Unwrap() error {
+	return ie.Err
+}
+
+// InputValues provides typed accessors of one input source.
+// Accessors return ErrInputMissing wrapped in *InputError if key is not given,
+// so zero value and missing value are different.
+type InputValues struct {
+	values goUrl.Values
+}
+
+// Query returns url query values.
+func (ctx *Context) Query() InputValues {
+	return InputValues{ctx.Request.URL.Query()}
+}
+
+// PostForm returns request body form values only.
+func (ctx *Context) PostForm() InputValues {
+	ctx.ParseForm()
+	return InputValues{ctx.Request.PostForm}
+}
+
+// Form returns both url query and body form values, as Input.
+func (ctx *Context) Form() InputValues {
+	ctx.ParseForm()
+	return InputValues{ctx.Request.Form}
+}
+
+// Params returns route param values.
+func (ctx *Context) Params() InputValues {
+	values := make(goUrl.Values)
+	for k, v := range ctx.routeParams {
+		values.Set(k, v)
+	}
+	return InputValues{values}
+}
+
+// Has checks key existing, even value is empty.
+func (iv InputValues) Has(key string) bool {
+	_, ok := iv.values[key]
+	return ok
+}
+
+// Lookup returns first value of key and whether key exists.
+func (iv InputValues) Lookup(key string) (string, bool) {
+	v, ok := iv.values[key]
+	if !ok || len(v) < 1 {
+		return "", false
+	}
+	return v[0], true
+}
+
+// String returns first value of key, or ErrInputMissing.
+func (iv InputValues) String(key string) (string, error) {
+	v, ok := iv.Lookup(key)
+	if !ok {
+		return "", &InputError{key, ErrInputMissing}
+	}
+	return v, nil
+}
+
+func (iv InputValues) parse(key string, fn func(string) error) error {
+	v, e := iv.String(key)
+	if e != nil {
+		return e
+	}
+	if e = fn(strings.TrimSpace(v)); e != nil {
+		return &InputError{key, e}
+	}
+	return nil
+}
+
+// Int returns int value of key.
+func (iv InputValues) Int(key string) (i int, e error) {
+	e = iv.parse(key, func(v string) (e error) {
+		i, e = strconv.Atoi(v)
+		return
+	})
+	return
+}
+
+// Int64 returns int64 value of key.
+func (iv InputValues) Int64(key string) (i int64, e error) {
+	e = iv.parse(key, func(v string) (e error) {
+		i, e = strconv.ParseInt(v, 10, 64)
+		return
+	})
+	return
+}
+
+// Uint returns uint value of key.
+func (iv InputValues) Uint(key string) (u uint, e error) {
+	e = iv.parse(key, func(v string) error {
+		u64, e := strconv.ParseUint(v, 10, 0)
+		u = uint(u64)
+		return e
+	})
+	return
+}
+
+// Float returns float64 value of key.
+func (iv InputValues) Float(key string) (f float64, e error) {
+	e = iv.parse(key, func(v string) (e error) {
+		f, e = strconv.ParseFloat(v, 64)
+		return
+	})
+	return
+}
+
+// Bool returns bool value of key.
+func (iv InputValues) Bool(key string) (b bool, e error) {
+	e = iv.parse(key, func(v string) (e error) {
+		b, e = strconv.ParseBool(v)
+		return
+	})
+	return
+}
+
+// Time returns time value of key with layout, as time.RFC3339 or "2006-01-02".
+func (iv InputValues) Time(key string, layout string) (t time.Time, e error) {
+	e = iv.parse(key, func(v string) (e error) {
+		t, e = time.Parse(layout, v)
+		return
+	})
+	return
+}
+
+// Duration returns duration value of key, as "1h30m".
+func (iv InputValues) Duration(key string) (d time.Duration, e error) {
+	e = iv.parse(key, func(v string) (e error) {
+		d, e = time.ParseDuration(v)
+		return
+	})
+	return
+}
+
+// Strings returns all values of key.
+// Comma-separated values are split, so "a,b&k=c" gives [a b c].
+func (iv InputValues) Strings(key string) ([]string, error) {
+	values, ok := iv.values[key]
+	if !ok {
+		return nil, &InputError{key, ErrInputMissing}
+	}
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				res = append(res, item)
+			}
+		}
+	}
+	return res, nil
+}
+
+// Ints returns int slice of key, split as Strings.
+func (iv InputValues) Ints(key string) ([]int, error) {
+	values, e := iv.Strings(key)
+	if e != nil {
+		return nil, e
+	}
+	res := make([]int, len(values))
+	for i, v := range values {
+		if res[i], e = strconv.Atoi(v); e != nil {
+			return nil, &InputError{key, e}
+		}
+	}
+	return res, nil
+}
+
+// Int64s returns int64 slice of key, split as Strings.
+func (iv InputValues) Int64s(key string) ([]int64, error) {
+	values, e := iv.Strings(key)
+	if e != nil {
+		return nil, e
+	}
+	res := make([]int64, len(values))
+	for i, v := range values {
+		if res[i], e = strconv.ParseInt(v, 10, 64); e != nil {
+			return nil, &InputError{key, e}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,296 @@
+package GoInk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	JWT_HS256 = "HS256"
+	JWT_RS256 = "RS256"
+	JWT_ES256 = "ES256"
+)
+
+var (
+	// ErrJwtMalformed means token is not three base64 json parts.
+	ErrJwtMalformed = errors.New("jwt is malformed")
+	// ErrJwtSignature means no key verifies token signature.
+	ErrJwtSignature = errors.New("jwt signature is invalid")
+	// ErrJwtExpired means token exp is passed.
+	ErrJwtExpired = errors.New("jwt is expired")
+	// ErrJwtNotValidYet means token nbf or iat is in future.
+	ErrJwtNotValidYet = errors.New("jwt is not valid yet")
+	// ErrJwtAudience means token aud doesn't contain expected audience.
+	ErrJwtAudience = errors.New("jwt audience is invalid")
+	// ErrJwtIssuer means token iss isn't expected issuer.
+	ErrJwtIssuer = errors.New("jwt issuer is invalid")
+)
+
+// JwtClaims is jwt payload map.
+type JwtClaims map[string]interface{}
+
+// String returns string claim by name.
+func (c JwtClaims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Time returns numeric date claim by name.
+func (c JwtClaims) Time(name string) (time.Time, bool) {
+	switch v := c[name].(type) {
+	case float64:
+		return time.Unix(int64(v), 0), true
+	case json.Number:
+		i, e := v.Int64()
+		return time.Unix(i, 0), e == nil
+	}
+	return time.Time{}, false
+}
+
+// JwtKey is signing or verifying key.
+// HS256 uses Secret, RS256 uses *rsa.PrivateKey or *rsa.PublicKey, ES256 uses P-256 *ecdsa.PrivateKey or *ecdsa.PublicKey.
+// Only public key is needed for verifying.
+type JwtKey struct {
+	// Key id, set as kid header
+	Id         string
+	Alg        string
+	Secret     []byte
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+func (k *JwtKey) publicKey() crypto.PublicKey {
+	if k.PublicKey == nil && k.PrivateKey != nil {
+		return k.PrivateKey.Public()
+	}
+	return k.PublicKey
+}
+
+func (k *JwtKey) sign(data []byte) ([]byte, error) {
+	hash := sha256.Sum256(data)
+	switch k.Alg {
+	case JWT_HS256:
+		mac := hmac.New(sha256.New, k.Secret)
+		mac.Write(data)
+		return mac.Sum(nil), nil
+	case JWT_RS256:
+		priv, ok := k.PrivateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("jwt RS256 key needs *rsa.PrivateKey")
+		}
+		return rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, hash[:])
+	case JWT_ES256:
+		priv, ok := k.PrivateKey.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("jwt ES256 key needs *ecdsa.PrivateKey")
+		}
+		r, s, e := ecdsa.Sign(rand.Reader, priv, hash[:])
+		if e != nil {
+			return nil, e
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig, nil
+	}
+	return nil, errors.New("unsupported jwt alg " + k.Alg)
+}
+
+func (k *JwtKey) verify(data []byte, sig []byte) bool {
+	hash := sha256.Sum256(data)
+	switch k.Alg {
+	case JWT_HS256:
+		if len(k.Secret) < 1 {
+			return false
+		}
+		mac := hmac.New(sha256.New, k.Secret)
+		mac.Write(data)
+		return hmac.Equal(sig, mac.Sum(nil))
+	case JWT_RS256:
+		pub, ok := k.publicKey().(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], sig) == nil
+	case JWT_ES256:
+		pub, ok := k.publicKey().(*ecdsa.PublicKey)
+		if !ok || len(sig) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(pub, hash[:], r, s)
+	}
+	return false
+}
+
+// Jwt instance signs and verifies json web tokens.
+type Jwt struct {
+	// Keys, the first one signs new tokens, all are tried by kid when verifying
+	Keys []*JwtKey
+	// Expected iss claim, not checked if empty
+	Issuer string
+	// Expected aud claim, not checked if empty
+	Audience string
+	// Allowed clock skew
+	Leeway time.Duration
+}
+
+// NewJwt returns jwt instance with keys.
+func NewJwt(keys ...*JwtKey) *Jwt {
+	return &Jwt{Keys: keys}
+}
+
+// Sign returns signed token of claims by first key.
+// Claim iat is set if missing, Issuer is set as iss if missing.
+func (j *Jwt) Sign(claims JwtClaims) (string, error) {
+	if len(j.Keys) < 1 {
+		return "", errors.New("jwt has no key")
+	}
+	key := j.Keys[0]
+	header := map[string]string{"alg": key.Alg, "typ": "JWT"}
+	if key.Id != "" {
+		header["kid"] = key.Id
+	}
+	payload := make(JwtClaims, len(claims)+2)
+	for k, v := range claims {
+		payload[k] = v
+	}
+	if _, ok := payload["iat"]; !ok {
+		payload["iat"] = time.Now().Unix()
+	}
+	if _, ok := payload["iss"]; !ok && j.Issuer != "" {
+		payload["iss"] = j.Issuer
+	}
+	h, e := json.Marshal(header)
+	if e != nil {
+		return "", e
+	}
+	p, e := json.Marshal(payload)
+	if e != nil {
+		return "", e
+	}
+	data := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(p)
+	sig, e := key.sign([]byte(data))
+	if e != nil {
+		return "", e
+	}
+	return data + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// Verify checks token signature and registered claims, then returns claims.
+func (j *Jwt) Verify(token string) (JwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrJwtMalformed
+	}
+	var header map[string]interface{}
+	if e := jwtDecode(parts[0], &header); e != nil {
+		return nil, e
+	}
+	sig, e := base64.RawURLEncoding.DecodeString(parts[2])
+	if e != nil {
+		return nil, ErrJwtMalformed
+	}
+	alg, _ := header["alg"].(string)
+	kid, _ := header["kid"].(string)
+	data := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range j.Keys {
+		// alg must match key, so "none" or alg confusion never passes
+		if key.Alg != alg || (kid != "" && key.Id != kid) {
+			continue
+		}
+		if key.verify(data, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrJwtSignature
+	}
+	claims := make(JwtClaims)
+	if e := jwtDecode(parts[1], &claims); e != nil {
+		return nil, e
+	}
+	if e := j.check(claims); e != nil {
+		return nil, e
+	}
+	return claims, nil
+}
+
+func jwtDecode(part string, v interface{}) error {
+	bytes, e := base64.RawURLEncoding.DecodeString(part)
+	if e != nil {
+		return ErrJwtMalformed
+	}
+	if json.Unmarshal(bytes, v) != nil {
+		return ErrJwtMalformed
+	}
+	return nil
+}
+
+func (j *Jwt) check(claims JwtClaims) error {
+	now := time.Now()
+	if exp, ok := claims.Time("exp"); ok && !now.Before(exp.Add(j.Leeway)) {
+		return ErrJwtExpired
+	}
+	if nbf, ok := claims.Time("nbf"); ok && now.Add(j.Leeway).Before(nbf) {
+		return ErrJwtNotValidYet
+	}
+	if iat, ok := claims.Time("iat"); ok && now.Add(j.Leeway).Before(iat) {
+		return ErrJwtNotValidYet
+	}
+	if j.Issuer != "" && claims.String("iss") != j.Issuer {
+		return ErrJwtIssuer
+	}
+	if j.Audience != "" {
+		switch aud := claims["aud"].(type) {
+		case string:
+			if aud == j.Audience {
+				return nil
+			}
+		case []interface{}:
+			for _, a := range aud {
+				if a == j.Audience {
+					return nil
+				}
+			}
+		}
+		return ErrJwtAudience
+	}
+	return nil
+}
+
+// Claims returns jwt claims attached by JwtAuth, or nil.
+func (ctx *Context) Claims() JwtClaims {
+	claims, _ := ctx.principal.(JwtClaims)
+	return claims
+}
+
+// JwtAuth returns handler verifying bearer jwt in Authorization header.
+// The claims are attached as context principal, read by Context.Claims.
+// Invalid token throws 401 with error description.
+func JwtAuth(j *Jwt) Handler {
+	return func(ctx *Context) {
+		auth := ctx.GetHeader("Authorization")
+		if len(auth) < 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+			unauthorized(ctx, `Bearer realm="jwt"`)
+			return
+		}
+		claims, e := j.Verify(strings.TrimSpace(auth[7:]))
+		if e != nil {
+			unauthorized(ctx, `Bearer realm="jwt", error="invalid_token", error_description=`+strconv.Quote(e.Error()))
+			return
+		}
+		ctx.principal = claims
+	}
+}
@@ -0,0 +1,165 @@
+package GoInk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// jwtToken signs header and claims by key without Jwt.Sign defaults, so tests can craft any token.
+func jwtToken(t *testing.T, key *JwtKey, header map[string]interface{}, claims JwtClaims) string {
+	t.Helper()
+	h, e := json.Marshal(header)
+	if e != nil {
+		t.Fatal(e)
+	}
+	p, e := json.Marshal(claims)
+	if e != nil {
+		t.Fatal(e)
+	}
+	data := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(p)
+	sig := []byte{}
+	if key != nil {
+		if sig, e = key.sign([]byte(data)); e != nil {
+			t.Fatal(e)
+		}
+	}
+	return data + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJwtVerify(t *testing.T) {
+	rsaKey, e := rsa.GenerateKey(rand.Reader, 2048)
+	if e != nil {
+		t.Fatal(e)
+	}
+	ecKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	rsaPub, e := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if e != nil {
+		t.Fatal(e)
+	}
+	hs := &JwtKey{Id: "h1", Alg: JWT_HS256, Secret: []byte("secret")}
+	rs := &JwtKey{Id: "r1", Alg: JWT_RS256, PrivateKey: rsaKey}
+	es := &JwtKey{Id: "e1", Alg: JWT_ES256, PrivateKey: ecKey}
+	// verifier only knows public keys of asymmetric algs
+	verifier := &Jwt{
+		Keys: []*JwtKey{
+			hs,
+			{Id: "r1", Alg: JWT_RS256, PublicKey: &rsaKey.PublicKey},
+			{Id: "e1", Alg: JWT_ES256, PublicKey: &ecKey.PublicKey},
+		},
+		Issuer:   "goink",
+		Audience: "api",
+		Leeway:   5 * time.Second,
+	}
+	now := time.Now().Unix()
+	claims := func(extra JwtClaims) JwtClaims {
+		c := JwtClaims{"sub": "1", "iss": "goink", "aud": "api", "exp": now + 60}
+		for k, v := range extra {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+	header := func(alg string, kid string) map[string]interface{} {
+		h := map[string]interface{}{"alg": alg, "typ": "JWT"}
+		if kid != "" {
+			h["kid"] = kid
+		}
+		return h
+	}
+	valid := jwtToken(t, hs, header(JWT_HS256, "h1"), claims(nil))
+	parts := strings.Split(valid, ".")
+	forged, _ := json.Marshal(claims(JwtClaims{"sub": "admin"}))
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"hs256", valid, nil},
+		{"rs256", jwtToken(t, rs, header(JWT_RS256, "r1"), claims(nil)), nil},
+		{"es256", jwtToken(t, es, header(JWT_ES256, "e1"), claims(nil)), nil},
+		{"no kid tries keys of alg", jwtToken(t, hs, header(JWT_HS256, ""), claims(nil)), nil},
+		{"alg none", jwtToken(t, nil, header("none", ""), claims(nil)), ErrJwtSignature},
+		{"alg none with kid", jwtToken(t, nil, header("none", "h1"), claims(nil)), ErrJwtSignature},
+		{"alg confusion HS256 by rsa public key", jwtToken(t, &JwtKey{Alg: JWT_HS256, Secret: rsaPub}, header(JWT_HS256, "r1"), claims(nil)), ErrJwtSignature},
+		{"alg mismatch with kid", jwtToken(t, es, header(JWT_ES256, "r1"), claims(nil)), ErrJwtSignature},
+		{"wrong kid", jwtToken(t, hs, header(JWT_HS256, "h2"), claims(nil)), ErrJwtSignature},
+		{"unknown secret", jwtToken(t, &JwtKey{Alg: JWT_HS256, Secret: []byte("other")}, header(JWT_HS256, "h1"), claims(nil)), ErrJwtSignature},
+		{"unknown rsa key", jwtToken(t, &JwtKey{Alg: JWT_RS256, PrivateKey: mustRsaKey(t)}, header(JWT_RS256, "r1"), claims(nil)), ErrJwtSignature},
+		{"tampered payload", parts[0] + "." + base64.RawURLEncoding.EncodeToString(forged) + "." + parts[2], ErrJwtSignature},
+		{"stripped signature", parts[0] + "." + parts[1] + ".", ErrJwtSignature},
+		{"expired", jwtToken(t, hs, header(JWT_HS256, "h1"), claims(JwtClaims{"exp": now - 60})), ErrJwtExpired},
+		{"expired in leeway", jwtToken(t, hs, header(JWT_HS256, "h1"), claims(JwtClaims{"exp": now - 2})), nil},
+		{"nbf in future", jwtToken(t, hs, header(JWT_HS256, "h1"), claims(JwtClaims{"nbf": now + 60})), ErrJwtNotValidYet},
+		{"nbf in leeway", jwtToken(t, hs, header(JWT_HS256, "h1"), claims(JwtClaims{"nbf": now + 2})), nil},
+		{"iat in future", jwtToken(t, hs, header(JWT_HS256, "h1"), claims(JwtClaims{"iat": now + 60})), ErrJwtNotValidYet},
+		{"wrong aud", jwtToken(t, hs, header(JWT_HS256, "h1"), claims(JwtClaims{"aud": "web"})), ErrJwtAudience},
+		{"missing aud", jwtToken(t, hs, header(JWT_HS256, "h1"), claims(JwtClaims{"aud": nil})), ErrJwtAudience},
+		{"aud list", jwtToken(t, hs, header(JWT_HS256, "h1"), claims(JwtClaims{"aud": []string{"web", "api"}})), nil},
+		{"aud list without expected", jwtToken(t, hs, header(JWT_HS256, "h1"), claims(JwtClaims{"aud": []string{"web"}})), ErrJwtAudience},
+		{"wrong iss", jwtToken(t, hs, header(JWT_HS256, "h1"), claims(JwtClaims{"iss": "other"})), ErrJwtIssuer},
+		{"two parts", parts[0] + "." + parts[1], ErrJwtMalformed},
+		{"bad header", "e30K!." + parts[1] + "." + parts[2], ErrJwtMalformed},
+		{"empty", "", ErrJwtMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, e := verifier.Verify(tt.token)
+			if e != tt.err {
+				t.Fatalf("Verify error = %v, want %v", e, tt.err)
+			}
+			if e == nil && c.String("sub") != "1" {
+				t.Fatalf("Verify sub = %q, want 1", c.String("sub"))
+			}
+		})
+	}
+}
+
+func TestJwtSignVerify(t *testing.T) {
+	j := &Jwt{Keys: []*JwtKey{{Id: "new", Alg: JWT_HS256, Secret: []byte("new")}}, Issuer: "goink"}
+	token, e := j.Sign(JwtClaims{"sub": "1", "exp": time.Now().Add(time.Minute).Unix()})
+	if e != nil {
+		t.Fatal(e)
+	}
+	c, e := j.Verify(token)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if c.String("iss") != "goink" {
+		t.Fatalf("iss = %q, want goink", c.String("iss"))
+	}
+	if _, ok := c.Time("iat"); !ok {
+		t.Fatal("iat is not set")
+	}
+	// rotated keys still verify old tokens, by kid
+	rotated := &Jwt{Keys: []*JwtKey{{Id: "newer", Alg: JWT_HS256, Secret: []byte("newer")}, j.Keys[0]}, Issuer: "goink"}
+	if _, e := rotated.Verify(token); e != nil {
+		t.Fatalf("rotated Verify error = %v", e)
+	}
+	if _, e := NewJwt().Sign(JwtClaims{}); e == nil {
+		t.Fatal("Sign without key should fail")
+	}
+}
+
+func mustRsaKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, e := rsa.GenerateKey(rand.Reader, 2048)
+	if e != nil {
+		t.Fatal(e)
+	}
+	return key
+}
@@ -0,0 +1,185 @@
+package GoInk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// LogLevel is severity of log message.
+type LogLevel int
+
+const (
+	LOG_DEBUG LogLevel = iota
+	LOG_INFO
+	LOG_WARN
+	LOG_ERROR
+)
+
+// String returns upper case level name.
+func (l LogLevel) String() string {
+	switch l {
+	case LOG_DEBUG:
+		return "DEBUG"
+	case LOG_INFO:
+		return "INFO"
+	case LOG_WARN:
+		return "WARN"
+	case LOG_ERROR:
+		return "ERROR"
+	}
+	return "LEVEL(" + strconv.Itoa(int(l)) + ")"
+}
+
+// ParseLogLevel returns level of name as "debug", "info", "warn" or "error", LOG_INFO if unknown.
+func ParseLogLevel(name string) LogLevel {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LOG_DEBUG
+	case "warn", "warning":
+		return LOG_WARN
+	case "error":
+		return LOG_ERROR
+	}
+	return LOG_INFO
+}
+
+// Logger writes leveled messages with structured fields.
+// Fields are key-value pairs, as:
+//
+//	logger.Error("session write error", "error", e, "id", id)
+type Logger interface {
+	Debug(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+	Warn(msg string, fields ...interface{})
+	Error(msg string, fields ...interface{})
+	// With returns logger adding fields to each message.
+	With(fields ...interface{}) Logger
+}
+
+// stdLogger writes "[LEVEL] message key=value" lines by *log.Logger.
+type stdLogger struct {
+	out    *log.Logger
+	level  LogLevel
+	fields []interface{}
+}
+
+// NewLogger returns logger writing text lines to w, messages below level are dropped.
+func NewLogger(w io.Writer, level LogLevel) Logger {
+	return NewStdLogger(log.New(w, "", log.LstdFlags), level)
+}
+
+// NewStdLogger returns logger writing text lines by standard *log.Logger.
+func NewStdLogger(l *log.Logger, level LogLevel) Logger {
+	return &stdLogger{out: l, level: level}
+}
+
+func (l *stdLogger) log(level LogLevel, msg string, fields []interface{}) {
+	if level < l.level {
+		return
+	}
+	var buf strings.Builder
+	buf.WriteString("[" + level.String() + "] " + msg)
+	writeLogFields(&buf, l.fields)
+	writeLogFields(&buf, fields)
+	l.out.Output(3, buf.String())
+}
+
+func (l *stdLogger) Debug(msg string, fields ...interface{}) {
+	l.log(LOG_DEBUG, msg, fields)
+}
+
+func (l *stdLogger) Info(msg string, fields ...interface{}) {
+	l.log(LOG_INFO, msg, fields)
+}
+
+func (l *stdLogger) Warn(msg string, fields ...interface{}) {
+	l.log(LOG_WARN, msg, fields)
+}
+
+func (l *stdLogger) Error(msg string, fields ...interface{}) {
+	l.log(LOG_ERROR, msg, fields)
+}
+
+func (l *stdLogger) With(fields ...interface{}) Logger {
+	all := make([]interface{}, 0, len(l.fields)+len(fields))
+	all = append(append(all, l.fields...), fields...)
+	return &stdLogger{out: l.out, level: l.level, fields: all}
+}
+
+// writeLogFields writes fields as key=value, values with spaces or quotes are quoted.
+// The value without key is written as !BADKEY=value like slog.
+func writeLogFields(buf *strings.Builder, fields []interface{}) {
+	for i := 0; i < len(fields); i += 2 {
+		key, value := "!BADKEY", fields[i]
+		if i+1 < len(fields) {
+			key, value = fmt.Sprint(fields[i]), fields[i+1]
+		}
+		str := fmt.Sprint(value)
+		if str == "" || strings.ContainsAny(str, " \t\r\n\"=") || !strconv.CanBackquote(str) {
+			str = strconv.Quote(str)
+		}
+		buf.WriteString(" " + key + "=" + str)
+	}
+}
+
+// slogLogger adapts *slog.Logger to Logger.
+type slogLogger struct {
+	out *slog.Logger
+}
+
+// NewSlogLogger returns logger writing by *slog.Logger, so its handler decides format and level.
+//
+//	app.SetLogger(GoInk.NewSlogLogger(slog.New(slog.NewJSONHandler(os.Stderr, nil))))
+func NewSlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{out: l}
+}
+
+func (l *slogLogger) Debug(msg string, fields ...interface{}) {
+	l.out.Log(context.Background(), slog.LevelDebug, msg, fields...)
+}
+
+func (l *slogLogger) Info(msg string, fields ...interface{}) {
+	l.out.Log(context.Background(), slog.LevelInfo, msg, fields...)
+}
+
+func (l *slogLogger) Warn(msg string, fields ...interface{}) {
+	l.out.Log(context.Background(), slog.LevelWarn, msg, fields...)
+}
+
+func (l *slogLogger) Error(msg string, fields ...interface{}) {
+	l.out.Log(context.Background(), slog.LevelError, msg, fields...)
+}
+
+func (l *slogLogger) With(fields ...interface{}) Logger {
+	return &slogLogger{out: l.out.With(fields...)}
+}
+
+// Logger returns global Logger instance, writing to stderr at config log.level by default.
+func (app *App) Logger() Logger {
+	return app.logger
+}
+
+// SetLogger replaces global logger, as NewSlogLogger for log/slog.
+func (app *App) SetLogger(l Logger) {
+	app.logger = l
+}
+
+// Logger returns request logger with request_id, method, path and matched route fields.
+func (ctx *Context) Logger() Logger {
+	if ctx.logger == nil {
+		fields := []interface{}{"method", ctx.Method, "path", ctx.Url}
+		if id := ctx.RequestId(); id != "" {
+			fields = append([]interface{}{"request_id", id}, fields...)
+		}
+		if ctx.route != nil {
+			fields = append(fields, "route", ctx.route.pattern)
+		}
+		ctx.logger = ctx.app.logger.With(fields...)
+	}
+	return ctx.logger
+}
@@ -0,0 +1,360 @@
+package GoInk
+
+import (
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	METRIC_COUNTER   = "counter"
+	METRIC_GAUGE     = "gauge"
+	METRIC_HISTOGRAM = "histogram"
+)
+
+// DefaultBuckets are histogram buckets in seconds for request latency.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var (
+	metricNameRegex  = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	metricLabelRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+type metricSeries struct {
+	values []string
+	value  float64
+	counts []uint64
+	sum    float64
+}
+
+// metric is one metric family with its series by label values.
+type metric struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*metricSeries
+	lock    sync.Mutex
+}
+
+// with calls fn with series of label values under lock.
+func (m *metric) with(values []string, fn func(s *metricSeries)) {
+	if len(values) != len(m.labels) {
+		panic("metric " + m.name + " needs " + strconv.Itoa(len(m.labels)) + " label values")
+	}
+	key := strings.Join(values, "\xff")
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	s, ok := m.series[key]
+	if !ok {
+		s = &metricSeries{values: append([]string{}, values...)}
+		if m.kind == METRIC_HISTOGRAM {
+			s.counts = make([]uint64, len(m.buckets)+1)
+		}
+		m.series[key] = s
+	}
+	fn(s)
+}
+
+// Counter is metric only going up, as requests total.
+type Counter struct {
+	m *metric
+}
+
+// Inc adds 1 to series of label values.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds v to series of label values, v must not be negative.
+func (c *Counter) Add(v float64, values ...string) {
+	if v < 0 {
+		panic("counter " + c.m.name + " can't decrease")
+	}
+	c.m.with(values, func(s *metricSeries) {
+		s.value += v
+	})
+}
+
+// Gauge is metric going up and down, as requests in flight.
+type Gauge struct {
+	m *metric
+}
+
+// Set sets v to series of label values.
+func (g *Gauge) Set(v float64, values ...string) {
+	g.m.with(values, func(s *metricSeries) {
+		s.value = v
+	})
+}
+
+// Add adds v to series of label values, v can be negative.
+func (g *Gauge) Add(v float64, values ...string) {
+	g.m.with(values, func(s *metricSeries) {
+		s.value += v
+	})
+}
+
+// Inc adds 1 to series of label values.
+func (g *Gauge) Inc(values ...string) {
+	g.Add(1, values...)
+}
+
+// Dec subtracts 1 from series of label values.
+func (g *Gauge) Dec(values ...string) {
+	g.Add(-1, values...)
+}
+
+// Histogram counts observed values in buckets, as request latency.
+type Histogram struct {
+	m *metric
+}
+
+// Observe adds v to series of label values.
+func (h *Histogram) Observe(v float64, values ...string) {
+	h.m.with(values, func(s *metricSeries) {
+		// bucket is upper inclusive bound, the last one is +Inf
+		s.counts[sort.SearchFloat64s(h.m.buckets, v)]++
+		s.sum += v
+	})
+}
+
+// Metrics is registry of metrics, exposed in Prometheus text format.
+type Metrics struct {
+	metrics map[string]*metric
+	lock    sync.Mutex
+}
+
+// NewMetrics returns empty metrics registry.
+// App creates one with built-in http metrics, returned by App.Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{metrics: make(map[string]*metric)}
+}
+
+// register returns metric of name, or creates it.
+// It panics if name or labels are invalid, or name is registered with different type or labels.
+func (ms *Metrics) register(name string, help string, kind string, buckets []float64, labels []string) *metric {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if m, ok := ms.metrics[name]; ok {
+		if m.kind != kind || strings.Join(m.labels, ",") != strings.Join(labels, ",") {
+			panic("metric " + name + " is registered as different " + m.kind)
+		}
+		return m
+	}
+	if !metricNameRegex.MatchString(name) {
+		panic("invalid metric name " + name)
+	}
+	for _, l := range labels {
+		if !metricLabelRegex.MatchString(l) || strings.HasPrefix(l, "__") || (kind == METRIC_HISTOGRAM && l == "le") {
+			panic("invalid label name " + l + " of metric " + name)
+		}
+	}
+	m := &metric{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: append([]string{}, labels...),
+		series: make(map[string]*metricSeries),
+	}
+	if kind == METRIC_HISTOGRAM {
+		if len(buckets) < 1 {
+			buckets = DefaultBuckets
+		}
+		m.buckets = make([]float64, 0, len(buckets))
+		for _, b := range buckets {
+			if !math.IsInf(b, 1) && !math.IsNaN(b) {
+				m.buckets = append(m.buckets, b)
+			}
+		}
+		sort.Float64s(m.buckets)
+		for i := len(m.buckets) - 1; i > 0; i-- {
+			if m.buckets[i] == m.buckets[i-1] {
+				m.buckets = append(m.buckets[:i], m.buckets[i+1:]...)
+			}
+		}
+	}
+	if len(labels) < 1 {
+		// series without labels is exposed as zero before first change
+		m.with(nil, func(s *metricSeries) {})
+	}
+	ms.metrics[name] = m
+	return m
+}
+
+// Counter returns counter of name with label names, registering it at first call.
+// So it's safe to call in handlers:
+//
+//	ctx.Metrics().Counter("orders_total", "Created orders.", "type").Inc("book")
+func (ms *Metrics) Counter(name string, help string, labels ...string) *Counter {
+	return &Counter{ms.register(name, help, METRIC_COUNTER, nil, labels)}
+}
+
+// Gauge returns gauge of name with label names, registering it at first call.
+func (ms *Metrics) Gauge(name string, help string, labels ...string) *Gauge {
+	return &Gauge{ms.register(name, help, METRIC_GAUGE, nil, labels)}
+}
+
+// Histogram returns histogram of name with buckets and label names, registering it at first call.
+// Nil buckets uses DefaultBuckets. Buckets are fixed by the first call.
+func (ms *Metrics) Histogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{ms.register(name, help, METRIC_HISTOGRAM, buckets, labels)}
+}
+
+// WriteTo writes all metrics in Prometheus text format, sorted by name and labels.
+func (ms *Metrics) WriteTo(w io.Writer) (int64, error) {
+	ms.lock.Lock()
+	list := make([]*metric, 0, len(ms.metrics))
+	for _, m := range ms.metrics {
+		list = append(list, m)
+	}
+	ms.lock.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].name < list[j].name
+	})
+	var buf strings.Builder
+	for _, m := range list {
+		m.write(&buf)
+	}
+	n, e := io.WriteString(w, buf.String())
+	return int64(n), e
+}
+
+func (m *metric) write(buf *strings.Builder) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if len(m.series) < 1 {
+		return
+	}
+	if m.help != "" {
+		buf.WriteString("# HELP " + m.name + " " + strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(m.help) + "\n")
+	}
+	buf.WriteString("# TYPE " + m.name + " " + m.kind + "\n")
+	keys := make([]string, 0, len(m.series))
+	for k := range m.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := m.series[k]
+		if m.kind != METRIC_HISTOGRAM {
+			buf.WriteString(m.name + metricLabels(m.labels, s.values, "", "") + " " + formatMetricValue(s.value) + "\n")
+			continue
+		}
+		var count uint64
+		for i, c := range s.counts {
+			count += c
+			le := "+Inf"
+			if i < len(m.buckets) {
+				le = formatMetricValue(m.buckets[i])
+			}
+			buf.WriteString(m.name + "_bucket" + metricLabels(m.labels, s.values, "le", le) + " " + strconv.FormatUint(count, 10) + "\n")
+		}
+		labels := metricLabels(m.labels, s.values, "", "")
+		buf.WriteString(m.name + "_sum" + labels + " " + formatMetricValue(s.sum) + "\n")
+		buf.WriteString(m.name + "_count" + labels + " " + strconv.FormatUint(count, 10) + "\n")
+	}
+}
+
+// metricLabels returns {name="value",...} with escaped values, and extra label if name not empty.
+func metricLabels(names []string, values []string, extra string, extraValue string) string {
+	if len(names) < 1 && extra == "" {
+		return ""
+	}
+	pairs := make([]string, 0, len(names)+1)
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	for i, n := range names {
+		pairs = append(pairs, n+`="`+escape.Replace(values[i])+`"`)
+	}
+	if extra != "" {
+		pairs = append(pairs, extra+`="`+extraValue+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatMetricValue(v float64) string {
+	// FormatFloat writes +Inf, -Inf and NaN as Prometheus does
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Handler returns handler writing metrics as response, registered to route:
+//
+//	app.Get("/metrics/", app.Metrics().Handler())
+//
+// Protect it by BasicAuth or network rules if it's public.
+func (ms *Metrics) Handler() Handler {
+	return func(ctx *Context) {
+		var buf strings.Builder
+		ms.WriteTo(&buf)
+		ctx.Header["Content-Type"] = "text/plain; version=0.0.4; charset=utf-8"
+		ctx.Body = []byte(buf.String())
+	}
+}
+
+// Metrics returns global *Metrics instance.
+func (app *App) Metrics() *Metrics {
+	return app.metrics
+}
+
+// Metrics returns global *Metrics instance for custom metrics in handlers.
+func (ctx *Context) Metrics() *Metrics {
+	return ctx.app.metrics
+}
+
+// httpMetrics are built-in metrics of App.handler.
+type httpMetrics struct {
+	requests    *Counter
+	duration    *Histogram
+	inFlight    *Gauge
+	panics      *Counter
+	cacheHits   *Counter
+	cacheMisses *Counter
+}
+
+func newHttpMetrics(ms *Metrics) *httpMetrics {
+	return &httpMetrics{
+		requests: ms.Counter("goink_http_requests_total",
+			"Total HTTP requests by method, route pattern and status.", "method", "route", "status"),
+		duration: ms.Histogram("goink_http_request_duration_seconds",
+			"HTTP request latency in seconds by method, route pattern and status.", nil, "method", "route", "status"),
+		inFlight: ms.Gauge("goink_http_requests_in_flight",
+			"HTTP requests being served."),
+		panics: ms.Counter("goink_http_panics_total",
+			"Recovered panics in handlers by method and route pattern.", "method", "route"),
+		cacheHits: ms.Counter("goink_router_cache_hits_total",
+			"Route lookups served by router cache."),
+		cacheMisses: ms.Counter("goink_router_cache_misses_total",
+			"Route lookups matched by router patterns."),
+	}
+}
+
+// metricRoute returns route pattern label, "unmatched" if route is not found.
+func metricRoute(ctx *Context) string {
+	if ctx.route == nil {
+		return "unmatched"
+	}
+	return ctx.route.pattern
+}
+
+// metricMethod returns method label, "OTHER" for unknown methods to limit series of bad requests.
+func metricMethod(ctx *Context) string {
+	switch ctx.Method {
+	case "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS":
+		return ctx.Method
+	}
+	return "OTHER"
+}
+
+// observe records finished request of context.
+func (hm *httpMetrics) observe(ctx *Context, start time.Time) {
+	hm.inFlight.Dec()
+	method, route, status := metricMethod(ctx), metricRoute(ctx), strconv.Itoa(ctx.Status)
+	hm.requests.Inc(method, route, status)
+	hm.duration.Observe(time.Since(start).Seconds(), method, route, status)
+}
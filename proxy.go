@@ -0,0 +1,128 @@
+package GoInk
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TrustProxy sets trusted proxy ips or CIDRs, as "10.0.0.0/8" or "127.0.0.1".
+// Forwarding headers are used only when request peer is trusted.
+// It's loaded from config app.trusted_proxies separated by comma in New.
+func (app *App) TrustProxy(proxies ...string) error {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			if strings.Contains(p, ":") {
+				p += "/128"
+			} else {
+				p += "/32"
+			}
+		}
+		_, n, e := net.ParseCIDR(p)
+		if e != nil {
+			return e
+		}
+		nets = append(nets, n)
+	}
+	app.proxies = nets
+	return nil
+}
+
+func (app *App) isTrustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range app.proxies {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHostIp returns ip without port and ipv6 brackets.
+func parseHostIp(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if host, _, e := net.SplitHostPort(addr); e == nil {
+		return host
+	}
+	return strings.Trim(addr, "[]")
+}
+
+// forwardedClient returns client ip, host and https flag from forwarding headers of trusted peer.
+// Forwarded header is preferred, then X-Forwarded-For, X-Real-IP, X-Forwarded-Host and X-Forwarded-Proto.
+// Host and proto are read only from the last hop, which is appended by the trusted peer,
+// as values before it can be written by client.
+func (app *App) forwardedClient(req *http.Request, peer string) (ip string, host string, isSSL bool) {
+	ip, host, isSSL = peer, req.Host, req.TLS != nil
+	if !app.isTrustedProxy(peer) {
+		return
+	}
+	var (
+		ips   []string
+		proto string
+	)
+	if fwd := req.Header.Values("Forwarded"); len(fwd) > 0 {
+		elements := strings.Split(strings.Join(fwd, ","), ",")
+		for i, element := range elements {
+			last := i == len(elements)-1
+			for _, pair := range strings.Split(element, ";") {
+				kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+				if len(kv) != 2 {
+					continue
+				}
+				value := strings.Trim(kv[1], `"`)
+				switch strings.ToLower(kv[0]) {
+				case "for":
+					ips = append(ips, parseHostIp(value))
+				case "proto":
+					if last {
+						proto = value
+					}
+				case "host":
+					if last {
+						host = value
+					}
+				}
+			}
+		}
+	} else {
+		if xff := req.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+			for _, v := range strings.Split(strings.Join(xff, ","), ",") {
+				ips = append(ips, parseHostIp(v))
+			}
+		} else if real := req.Header.Get("X-Real-IP"); real != "" {
+			ips = append(ips, parseHostIp(real))
+		}
+		if h := lastHeaderValue(req, "X-Forwarded-Host"); h != "" {
+			host = h
+		}
+		proto = lastHeaderValue(req, "X-Forwarded-Proto")
+	}
+	// the right-most untrusted address is the client, others may be spoofed
+	for i := len(ips) - 1; i >= 0; i-- {
+		if net.ParseIP(ips[i]) == nil {
+			break
+		}
+		ip = ips[i]
+		if !app.isTrustedProxy(ips[i]) {
+			break
+		}
+	}
+	if proto != "" {
+		isSSL = strings.EqualFold(proto, "https")
+	}
+	return
+}
+
+// lastHeaderValue returns the last comma-separated value of header name.
+func lastHeaderValue(req *http.Request, name string) string {
+	values := req.Header.Values(name)
+	if len(values) < 1 {
+		return ""
+	}
+	list := strings.Split(values[len(values)-1], ",")
+	return strings.TrimSpace(list[len(list)-1])
+}
@@ -0,0 +1,55 @@
+package GoInk
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForwardedClient(t *testing.T) {
+	app := New()
+	if e := app.TrustProxy("10.0.0.0/8", "::1"); e != nil {
+		t.Fatal(e)
+	}
+	tests := []struct {
+		name    string
+		peer    string
+		headers map[string]string
+		ip      string
+		host    string
+		ssl     bool
+	}{
+		{"untrusted peer", "6.6.6.6:1000", map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Forwarded-Host": "evil.com", "X-Forwarded-Proto": "https"}, "6.6.6.6", "example.com", false},
+		{"no headers", "10.0.0.1:1000", nil, "10.0.0.1", "example.com", false},
+		{"ipv6 peer", "[::1]:1000", map[string]string{"X-Forwarded-For": "1.2.3.4"}, "1.2.3.4", "example.com", false},
+		{"xff", "10.0.0.1:1000", map[string]string{"X-Forwarded-For": "1.2.3.4"}, "1.2.3.4", "example.com", false},
+		{"xff spoofed left", "10.0.0.1:1000", map[string]string{"X-Forwarded-For": "6.6.6.6, 1.2.3.4, 10.0.0.2"}, "1.2.3.4", "example.com", false},
+		{"xff invalid", "10.0.0.1:1000", map[string]string{"X-Forwarded-For": "1.2.3.4, bad"}, "10.0.0.1", "example.com", false},
+		{"x-real-ip", "10.0.0.1:1000", map[string]string{"X-Real-IP": "1.2.3.4"}, "1.2.3.4", "example.com", false},
+		{"x-forwarded host and proto", "10.0.0.1:1000", map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Forwarded-Host": "app.com", "X-Forwarded-Proto": "https"}, "1.2.3.4", "app.com", true},
+		{"x-forwarded spoofed first values", "10.0.0.1:1000", map[string]string{"X-Forwarded-Host": "evil.com, app.com", "X-Forwarded-Proto": "https, http"}, "10.0.0.1", "app.com", false},
+		{"forwarded", "10.0.0.1:1000", map[string]string{"Forwarded": `for="[2001:db8::1]:4711";proto=https;host=app.com`}, "2001:db8::1", "app.com", true},
+		{"forwarded spoofed element", "10.0.0.1:1000", map[string]string{"Forwarded": "for=6.6.6.6;proto=https;host=evil.com, for=1.2.3.4"}, "1.2.3.4", "example.com", false},
+		{"forwarded spoofed element before proxy", "10.0.0.1:1000", map[string]string{"Forwarded": "for=6.6.6.6;proto=https;host=evil.com, for=1.2.3.4;proto=http;host=app.com"}, "1.2.3.4", "app.com", false},
+		{"forwarded preferred", "10.0.0.1:1000", map[string]string{"Forwarded": "for=1.2.3.4", "X-Forwarded-For": "5.6.7.8"}, "1.2.3.4", "example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://example.com/", nil)
+			req.RemoteAddr = tt.peer
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			ctx := NewContext(app, httptest.NewRecorder(), req)
+			if ctx.Ip != tt.ip || ctx.Host != tt.host || ctx.IsSSL != tt.ssl {
+				t.Fatalf("Ip, Host, IsSSL = %s, %s, %v, want %s, %s, %v", ctx.Ip, ctx.Host, ctx.IsSSL, tt.ip, tt.host, tt.ssl)
+			}
+			base := "http://" + tt.host + "/"
+			if tt.ssl {
+				base = "https://" + tt.host + "/"
+			}
+			if ctx.Base != base {
+				t.Fatalf("Base = %s, want %s", ctx.Base, base)
+			}
+		})
+	}
+}
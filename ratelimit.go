@@ -0,0 +1,220 @@
+package GoInk
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateState is saved state of one rate limit key.
+// Token bucket uses Value as tokens, sliding window uses Value and Prev as counts of current and previous window.
+type RateState struct {
+	Value float64
+	Prev  float64
+	Time  time.Time
+}
+
+// RateStore saves rate limit states.
+type RateStore interface {
+	// Update loads state of key, calls fn to change it and saves it with ttl atomically.
+	// New key gets zero RateState.
+	Update(key string, ttl time.Duration, fn func(state *RateState))
+}
+
+// RateResult is result of one rate limit check.
+type RateResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// RateAlgorithm consumes one request from state and returns result.
+type RateAlgorithm func(state *RateState, now time.Time, limit int, period time.Duration) RateResult
+
+// TokenBucket allows bursts up to limit, refilling limit tokens every period.
+func TokenBucket(state *RateState, now time.Time, limit int, period time.Duration) RateResult {
+	rate := float64(limit) / float64(period)
+	if state.Time.IsZero() {
+		state.Value = float64(limit)
+	} else {
+		state.Value = math.Min(float64(limit), state.Value+float64(now.Sub(state.Time))*rate)
+	}
+	state.Time = now
+	res := RateResult{Limit: limit}
+	if state.Value >= 1 {
+		state.Value--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - state.Value) / rate)
+	}
+	res.Remaining = int(state.Value)
+	res.Reset = time.Duration((float64(limit) - state.Value) / rate)
+	return res
+}
+
+// SlidingWindow allows limit requests in any period, weighting previous window count by overlap.
+func SlidingWindow(state *RateState, now time.Time, limit int, period time.Duration) RateResult {
+	start := now.Truncate(period)
+	switch {
+	case state.Time.Equal(start):
+	case state.Time.Add(period).Equal(start):
+		state.Prev, state.Value = state.Value, 0
+	default:
+		state.Prev, state.Value = 0, 0
+	}
+	state.Time = start
+	elapsed := now.Sub(start)
+	weight := float64(period-elapsed) / float64(period)
+	count := state.Prev*weight + state.Value
+	res := RateResult{Limit: limit, Reset: period - elapsed}
+	if count+1 <= float64(limit) {
+		state.Value++
+		count++
+		res.Allowed = true
+	} else if state.Prev > 0 {
+		// wait until previous window weight drops enough
+		need := (count + 1 - float64(limit)) / state.Prev
+		res.RetryAfter = time.Duration(need * float64(period))
+		if res.RetryAfter > res.Reset {
+			res.RetryAfter = res.Reset
+		}
+	} else {
+		res.RetryAfter = res.Reset
+	}
+	res.Remaining = int(math.Max(0, float64(limit)-count))
+	return res
+}
+
+type memoryRateItem struct {
+	state  RateState
+	expire time.Time
+}
+
+// MemoryRateStore saves rate limit states in memory.
+// Expired states are removed by gc goroutine, stopped by Close.
+type MemoryRateStore struct {
+	storeGC
+	items map[string]*memoryRateItem
+	lock  sync.Mutex
+}
+
+// NewMemoryRateStore returns memory store and removes expired states every interval duration.
+// Zero interval doesn't start gc, so call GC by yourself.
+func NewMemoryRateStore(interval time.Duration) *MemoryRateStore {
+	ms := &MemoryRateStore{items: make(map[string]*memoryRateItem)}
+	ms.start(interval, ms.GC)
+	return ms
+}
+
+// Update changes state of key.
+func (ms *MemoryRateStore) Update(key string, ttl time.Duration, fn func(state *RateState)) {
+	now := time.Now()
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	item, ok := ms.items[key]
+	if !ok || now.After(item.expire) {
+		item = new(memoryRateItem)
+		ms.items[key] = item
+	}
+	fn(&item.state)
+	item.expire = now.Add(ttl)
+}
+
+// GC removes expired states.
+func (ms *MemoryRateStore) GC() {
+	now := time.Now()
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	for key, item := range ms.items {
+		if now.After(item.expire) {
+			delete(ms.items, key)
+		}
+	}
+}
+
+// RateKeyIp returns client ip as rate limit key.
+func RateKeyIp(ctx *Context) string {
+	return ctx.Ip
+}
+
+// RateKeyUser returns principal as rate limit key, or client ip if not authenticated.
+func RateKeyUser(ctx *Context) string {
+	if ctx.principal == nil {
+		return "ip:" + ctx.Ip
+	}
+	if claims, ok := ctx.principal.(JwtClaims); ok {
+		return "user:" + claims.String("sub")
+	}
+	return "user:" + fmt.Sprint(ctx.principal)
+}
+
+// RateOption defines rate limit settings.
+type RateOption struct {
+	// Key prefix in store, so limiters can share one store
+	Name string
+	// Allowed requests in period
+	Limit  int
+	Period time.Duration
+	// TokenBucket by default
+	Algorithm RateAlgorithm
+	// MemoryRateStore by default
+	Store RateStore
+	// RateKeyIp by default
+	Key func(ctx *Context) string
+	// Handler when limit exceeded, throw 429 by default
+	Exceeded Handler
+}
+
+// RateLimit returns handler limiting requests per key.
+// It's used as middleware or route handler for per-route limits:
+//
+//	app.Post("/login/", GoInk.RateLimit(&GoInk.RateOption{Limit: 5, Period: time.Minute}), loginHandler)
+//
+// It sets X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset headers, and Retry-After when exceeded.
+// It panics if Limit or Period is not positive.
+// The default MemoryRateStore runs gc goroutine for app lifetime, share a store to limit goroutines.
+func RateLimit(opt *RateOption) Handler {
+	if opt.Limit <= 0 || opt.Period <= 0 {
+		panic("rate limit needs positive Limit and Period")
+	}
+	if opt.Name == "" {
+		opt.Name = randomString(4)
+	}
+	if opt.Algorithm == nil {
+		opt.Algorithm = TokenBucket
+	}
+	if opt.Store == nil {
+		opt.Store = NewMemoryRateStore(opt.Period * 2)
+	}
+	if opt.Key == nil {
+		opt.Key = RateKeyIp
+	}
+	if opt.Exceeded == nil {
+		opt.Exceeded = func(ctx *Context) {
+			ctx.Throw(http.StatusTooManyRequests, "too many requests")
+		}
+	}
+	return func(ctx *Context) {
+		var res RateResult
+		now := time.Now()
+		opt.Store.Update(opt.Name+":"+opt.Key(ctx), opt.Period*2, func(state *RateState) {
+			res = opt.Algorithm(state, now, opt.Limit, opt.Period)
+		})
+		ctx.Header["X-RateLimit-Limit"] = strconv.Itoa(res.Limit)
+		ctx.Header["X-RateLimit-Remaining"] = strconv.Itoa(res.Remaining)
+		ctx.Header["X-RateLimit-Reset"] = strconv.Itoa(int(math.Ceil(res.Reset.Seconds())))
+		if res.Allowed {
+			return
+		}
+		ctx.Header["Retry-After"] = strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds())))
+		opt.Exceeded(ctx)
+		if !ctx.IsEnd {
+			ctx.End()
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package GoInk
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	for _, opt := range []*RateOption{{Limit: 0, Period: time.Second}, {Limit: 1, Period: 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("RateLimit(%+v) should panic", opt)
+				}
+			}()
+			RateLimit(opt)
+		}()
+	}
+
+	for name, algorithm := range map[string]RateAlgorithm{"token bucket": TokenBucket, "sliding window": SlidingWindow} {
+		t.Run(name, func(t *testing.T) {
+			store := NewMemoryRateStore(0)
+			defer store.Close()
+			app := New()
+			app.Get("/", RateLimit(&RateOption{Limit: 2, Period: time.Minute, Algorithm: algorithm, Store: store}), func(ctx *Context) {
+				ctx.Body = []byte("ok")
+			})
+			for i, status := range []int{200, 200, 429} {
+				w := httptest.NewRecorder()
+				app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+				if w.Code != status {
+					t.Fatalf("request %d status = %d, want %d", i, w.Code, status)
+				}
+				if status == 429 && w.Header().Get("Retry-After") == "" {
+					t.Fatal("Retry-After is not set")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package GoInk
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// requestIdKey is context.Context key of request id.
+type requestIdKey struct{}
+
+// RequestIdOption defines request id settings.
+type RequestIdOption struct {
+	// Request and response header name, "X-Request-ID" by default
+	Header string
+	// Generates new id if request has no valid one, 16 random bytes in hex by default
+	Generator func() string
+}
+
+// NewRequestIdOption returns request id option from config:
+//
+//	request_id.header  "X-Request-ID" by default
+func NewRequestIdOption(cfg *Config) *RequestIdOption {
+	opt := &RequestIdOption{Header: cfg.String("request_id.header")}
+	if opt.Header == "" {
+		opt.Header = "X-Request-ID"
+	}
+	return opt
+}
+
+// RequestId returns middleware handler reading request id from header or generating a new one.
+// Nil option loads settings by NewRequestIdOption from app config.
+// The id is echoed in response header, added to Context.Logger and access log,
+// and saved in Context.Request context for outgoing requests by RequestIdTransport.
+func RequestId(opt *RequestIdOption) Handler {
+	var once sync.Once
+	return func(ctx *Context) {
+		once.Do(func() {
+			if opt == nil {
+				opt = NewRequestIdOption(ctx.app.config)
+			}
+			if opt.Generator == nil {
+				opt.Generator = func() string {
+					return randomString(16)
+				}
+			}
+		})
+		id := ctx.GetHeader(opt.Header)
+		if !validRequestId(id) {
+			id = opt.Generator()
+		}
+		ctx.requestId = id
+		ctx.logger = nil
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), requestIdKey{}, id))
+		ctx.Header[opt.Header] = id
+	}
+}
+
+// validRequestId accepts printable ascii id up to 128 chars, so client can't inject log lines.
+func validRequestId(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestId returns request id set by RequestId middleware, or empty string.
+func (ctx *Context) RequestId() string {
+	return ctx.requestId
+}
+
+// Context returns context.Context of request, carrying request id.
+// Use it for outgoing requests, database calls and so on:
+//
+//	req, _ := http.NewRequestWithContext(ctx.Context(), "GET", url, nil)
+func (ctx *Context) Context() context.Context {
+	return ctx.Request.Context()
+}
+
+// RequestIdFromContext returns request id in context.Context, or empty string.
+func RequestIdFromContext(c context.Context) string {
+	id, _ := c.Value(requestIdKey{}).(string)
+	return id
+}
+
+// RequestIdTransport adds request id from request context to header of outgoing requests.
+//
+//	client := &http.Client{Transport: &GoInk.RequestIdTransport{}}
+type RequestIdTransport struct {
+	// Header name, "X-Request-ID" by default
+	Header string
+	// Wrapped transport, http.DefaultTransport by default
+	Base http.RoundTripper
+}
+
+// RoundTrip sends request with request id header if not set.
+func (t *RequestIdTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	header := t.Header
+	if header == "" {
+		header = "X-Request-ID"
+	}
+	if id := RequestIdFromContext(req.Context()); id != "" && req.Header.Get(header) == "" {
+		// RoundTripper must not modify request
+		req = req.Clone(req.Context())
+		req.Header.Set(header, id)
+	}
+	return base.RoundTrip(req)
+}
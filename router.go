@@ -8,10 +8,11 @@ import (
 )
 
 const (
-	ROUTER_METHOD_GET    = "GET"
-	ROUTER_METHOD_POST   = "POST"
-	ROUTER_METHOD_PUT    = "PUT"
-	ROUTER_METHOD_DELETE = "DELETE"
+	ROUTER_METHOD_GET     = "GET"
+	ROUTER_METHOD_POST    = "POST"
+	ROUTER_METHOD_PUT     = "PUT"
+	ROUTER_METHOD_DELETE  = "DELETE"
+	ROUTER_METHOD_OPTIONS = "OPTIONS"
 )
 
 // Router instance provides router pattern and handlers.
@@ -32,40 +33,39 @@ func newRoute() *Route {
 	return route
 }
 
-// Get registers GET handlers with pattern string.
-func (rt *Router) Get(pattern string, fn ...Handler) {
+func (rt *Router) add(method string, pattern string, fn []Handler) *Route {
 	route := newRoute()
 	route.regex, route.params = rt.parsePattern(pattern)
-	route.method = ROUTER_METHOD_GET
+	route.pattern = pattern
+	route.method = method
 	route.fn = fn
 	rt.routeSlice = append(rt.routeSlice, route)
+	return route
+}
+
+// Get registers GET handlers with pattern string.
+func (rt *Router) Get(pattern string, fn ...Handler) *Route {
+	return rt.add(ROUTER_METHOD_GET, pattern, fn)
 }
 
 // Post registers POST handlers with pattern string.
-func (rt *Router) Post(pattern string, fn ...Handler) {
-	route := newRoute()
-	route.regex, route.params = rt.parsePattern(pattern)
-	route.method = ROUTER_METHOD_POST
-	route.fn = fn
-	rt.routeSlice = append(rt.routeSlice, route)
+func (rt *Router) Post(pattern string, fn ...Handler) *Route {
+	return rt.add(ROUTER_METHOD_POST, pattern, fn)
 }
 
 // Put registers PUT handlers with pattern string.
-func (rt *Router) Put(pattern string, fn ...Handler) {
-	route := newRoute()
-	route.regex, route.params = rt.parsePattern(pattern)
-	route.method = ROUTER_METHOD_PUT
-	route.fn = fn
-	rt.routeSlice = append(rt.routeSlice, route)
+func (rt *Router) Put(pattern string, fn ...Handler) *Route {
+	return rt.add(ROUTER_METHOD_PUT, pattern, fn)
 }
 
 // Delete registers DELETE handlers with pattern string.
-func (rt *Router) Delete(pattern string, fn ...Handler) {
-	route := newRoute()
-	route.regex, route.params = rt.parsePattern(pattern)
-	route.method = ROUTER_METHOD_DELETE
-	route.fn = fn
-	rt.routeSlice = append(rt.routeSlice, route)
+func (rt *Router) Delete(pattern string, fn ...Handler) *Route {
+	return rt.add(ROUTER_METHOD_DELETE, pattern, fn)
+}
+
+// Options registers OPTIONS handlers with pattern string.
+func (rt *Router) Options(pattern string, fn ...Handler) *Route {
+	return rt.add(ROUTER_METHOD_OPTIONS, pattern, fn)
 }
 
 func (rt *Router) parsePattern(pattern string) (regex *regexp.Regexp, params []string) {
@@ -83,6 +83,15 @@ func (rt *Router) parsePattern(pattern string) (regex *regexp.Regexp, params []s
 
 // Find does find matched rule and parse route url, returns route params and matched handlers.
 func (rt *Router) Find(url string, method string) (params map[string]string, fn []Handler) {
+	route, params := rt.match(url, method)
+	if route == nil {
+		return nil, nil
+	}
+	return params, route.fn
+}
+
+// match returns matched route and its params of url.
+func (rt *Router) match(url string, method string) (*Route, map[string]string) {
 	sfx := path.Ext(url)
 	url = strings.Replace(url, sfx, "", -1)
 	// fix path end slash
@@ -97,30 +106,51 @@ func (rt *Router) Find(url string, method string) (params map[string]string, fn
 			if len(p) != len(r.params)+1 {
 				continue
 			}
-			params = make(map[string]string)
+			params := make(map[string]string)
 			for i, n := range r.params {
 				params[n] = p[i+1]
 			}
-			fn = r.fn
-			return
+			return r, params
 		}
 	}
 	return nil, nil
 }
 
+// Routes returns information of all registered routes in order.
+func (rt *Router) Routes() []RouteInfo {
+	info := make([]RouteInfo, len(rt.routeSlice))
+	for i, r := range rt.routeSlice {
+		info[i] = RouteInfo{Method: r.method, Pattern: r.pattern, Permissions: r.perms}
+	}
+	return info
+}
+
 // Route struct defines route pattern rule item.
 type Route struct {
-	regex  *regexp.Regexp
-	method string
-	params []string
-	fn     []Handler
+	regex   *regexp.Regexp
+	pattern string
+	method  string
+	params  []string
+	fn      []Handler
+	perms   []string
+	// count of group shared handlers running before permission check
+	shared int
+	// request body size limit, set by Route.BodyLimit
+	bodyMax int64
+}
+
+// RouteInfo describes registered route for introspection.
+type RouteInfo struct {
+	Method      string
+	Pattern     string
+	Permissions []string
 }
 
 // Handler defines route handler, middleware handler type.
 type Handler func(context *Context)
 
-// router cache, save route param for caching.
+// router cache, save route param for caching by method and path.
 type routerCache struct {
 	param map[string]string
-	fn    []Handler
+	route *Route
 }
@@ -0,0 +1,58 @@
+package GoInk
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// ErrDecrypt means encrypted data is tampered or the key is wrong.
+var ErrDecrypt = errors.New("decrypt data failed")
+
+// randomString returns hex string of n random bytes.
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, e := io.ReadFull(rand.Reader, b); e != nil {
+		panic(e)
+	}
+	return hex.EncodeToString(b)
+}
+
+// newAEAD returns AES-256-GCM cipher with key derived from secret string.
+func newAEAD(secret string) cipher.AEAD {
+	key := sha256.Sum256([]byte(secret))
+	block, e := aes.NewCipher(key[:])
+	if e != nil {
+		panic(e)
+	}
+	aead, e := cipher.NewGCM(block)
+	if e != nil {
+		panic(e)
+	}
+	return aead
+}
+
+// encrypt seals data and additional data with random nonce, nonce is prefixed to result.
+func encrypt(aead cipher.AEAD, data []byte, ad []byte) []byte {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, e := io.ReadFull(rand.Reader, nonce); e != nil {
+		panic(e)
+	}
+	return aead.Seal(nonce, nonce, data, ad)
+}
+
+// decrypt opens data sealed by encrypt.
+func decrypt(aead cipher.AEAD, data []byte, ad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	res, e := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], ad)
+	if e != nil {
+		return nil, ErrDecrypt
+	}
+	return res, nil
+}
@@ -0,0 +1,121 @@
+package GoInk
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SecureOption defines security response headers.
+// Empty string or zero value disables the header.
+type SecureOption struct {
+	// Strict-Transport-Security max-age seconds, only sent on https
+	HSTSMaxAge            int
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	// X-Frame-Options, as "DENY" or "SAMEORIGIN"
+	FrameOptions string
+	// X-Content-Type-Options: nosniff
+	NoSniff bool
+	// Referrer-Policy
+	ReferrerPolicy string
+	// Content-Security-Policy, "{nonce}" is replaced by per-request nonce source
+	ContentSecurityPolicy string
+}
+
+// NewSecureOption returns secure option with secure defaults, overridden by config:
+//
+//	secure.hsts_max_age             31536000 by default
+//	secure.hsts_include_subdomains  true by default
+//	secure.hsts_preload
+//	secure.frame_options            "SAMEORIGIN" by default
+//	secure.no_sniff                 true by default
+//	secure.referrer_policy          "strict-origin-when-cross-origin" by default
+//	secure.csp                      "default-src 'self'; script-src 'self' {nonce}; object-src 'none'" by default
+func NewSecureOption(cfg *Config) *SecureOption {
+	opt := &SecureOption{
+		HSTSMaxAge:            31536000,
+		HSTSIncludeSubdomains: true,
+		FrameOptions:          "SAMEORIGIN",
+		NoSniff:               true,
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		ContentSecurityPolicy: "default-src 'self'; script-src 'self' {nonce}; object-src 'none'",
+	}
+	if cfg.String("secure.hsts_max_age") != "" {
+		opt.HSTSMaxAge = cfg.Int("secure.hsts_max_age")
+	}
+	if cfg.String("secure.hsts_include_subdomains") != "" {
+		opt.HSTSIncludeSubdomains = cfg.Bool("secure.hsts_include_subdomains")
+	}
+	opt.HSTSPreload = cfg.Bool("secure.hsts_preload")
+	if _, ok := (*cfg)["secure"]["frame_options"]; ok {
+		opt.FrameOptions = cfg.String("secure.frame_options")
+	}
+	if cfg.String("secure.no_sniff") != "" {
+		opt.NoSniff = cfg.Bool("secure.no_sniff")
+	}
+	if _, ok := (*cfg)["secure"]["referrer_policy"]; ok {
+		opt.ReferrerPolicy = cfg.String("secure.referrer_policy")
+	}
+	if _, ok := (*cfg)["secure"]["csp"]; ok {
+		opt.ContentSecurityPolicy = cfg.String("secure.csp")
+	}
+	return opt
+}
+
+// CspNonce returns content security policy nonce of this context.
+// It's empty if SecureHeaders middleware is not used or policy has no {nonce}.
+func (ctx *Context) CspNonce() string {
+	return ctx.cspNonce
+}
+
+// SecureHeaders returns middleware handler that sets security response headers.
+// Nil option loads settings by NewSecureOption from app config.
+// If policy contains {nonce}, the nonce is assigned as view data "CspNonce":
+//
+//	<script nonce="{{.CspNonce}}">...</script>
+func SecureHeaders(opt *SecureOption) Handler {
+	var once sync.Once
+	return func(ctx *Context) {
+		once.Do(func() {
+			if opt == nil {
+				opt = NewSecureOption(ctx.app.config)
+			}
+		})
+		if opt.HSTSMaxAge > 0 && ctx.IsSSL {
+			hsts := "max-age=" + strconv.Itoa(opt.HSTSMaxAge)
+			if opt.HSTSIncludeSubdomains {
+				hsts += "; includeSubDomains"
+			}
+			if opt.HSTSPreload {
+				hsts += "; preload"
+			}
+			ctx.Header["Strict-Transport-Security"] = hsts
+		}
+		if opt.FrameOptions != "" {
+			ctx.Header["X-Frame-Options"] = opt.FrameOptions
+		}
+		if opt.NoSniff {
+			ctx.Header["X-Content-Type-Options"] = "nosniff"
+		}
+		if opt.ReferrerPolicy != "" {
+			ctx.Header["Referrer-Policy"] = opt.ReferrerPolicy
+		}
+		if opt.ContentSecurityPolicy == "" {
+			return
+		}
+		csp := opt.ContentSecurityPolicy
+		if strings.Contains(csp, "{nonce}") {
+			b := make([]byte, 16)
+			if _, e := rand.Read(b); e != nil {
+				panic(e)
+			}
+			ctx.cspNonce = base64.StdEncoding.EncodeToString(b)
+			ctx.Assign("CspNonce", ctx.cspNonce)
+			csp = strings.Replace(csp, "{nonce}", "'nonce-"+ctx.cspNonce+"'", -1)
+		}
+		ctx.Header["Content-Security-Policy"] = csp
+	}
+}
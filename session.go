@@ -0,0 +1,169 @@
+package GoInk
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	CONTEXT_BEFORE_SEND = "context_before_send"
+)
+
+// SessionStore saves encoded session data by session id.
+type SessionStore interface {
+	// Read returns session data of id, or nil if not found or expired.
+	Read(id string) ([]byte, error)
+	// Write saves session data of id with ttl, and returns the value saved in cookie.
+	Write(id string, data []byte, ttl time.Duration) (string, error)
+	// Remove deletes session data of id.
+	Remove(id string) error
+}
+
+// Session instance holds session data of one request.
+// Values are saved as json, so numbers are float64 after reading from store.
+type Session struct {
+	id        string
+	data      map[string]interface{}
+	isNew     bool
+	isChanged bool
+	isDestroy bool
+	store     SessionStore
+}
+
+// Id returns session id.
+func (s *Session) Id() string {
+	return s.id
+}
+
+// Get returns session value by key.
+func (s *Session) Get(key string) interface{} {
+	return s.data[key]
+}
+
+// Set sets session value by key.
+func (s *Session) Set(key string, v interface{}) {
+	s.data[key] = v
+	s.isChanged = true
+}
+
+// Delete removes session value by key.
+func (s *Session) Delete(key string) {
+	if _, ok := s.data[key]; ok {
+		delete(s.data, key)
+		s.isChanged = true
+	}
+}
+
+// Regenerate changes session id and keeps data.
+// It should be called after login to prevent session fixation.
+func (s *Session) Regenerate() error {
+	if !s.isNew {
+		if e := s.store.Remove(s.id); e != nil {
+			return e
+		}
+	}
+	s.id = randomString(16)
+	s.isNew = true
+	s.isChanged = true
+	return nil
+}
+
+// Destroy removes session data from store and deletes session cookie.
+func (s *Session) Destroy() error {
+	s.data = make(map[string]interface{})
+	s.isDestroy = true
+	if s.isNew {
+		return nil
+	}
+	return s.store.Remove(s.id)
+}
+
+// Session returns session of this context.
+// It panics if Sessions middleware is not used.
+func (ctx *Context) Session() *Session {
+	if ctx.session == nil {
+		panic("session is not enabled, use GoInk.Sessions middleware")
+	}
+	return ctx.session
+}
+
+// Sessions returns middleware handler that loads session from store and saves it before sending response.
+// The session cookie is set by config:
+//
+//	session.cookie     cookie name, "GOINK_SESSION" by default
+//	session.ttl        seconds of session life, 86400 by default
+//	session.path       cookie path, "/" by default
+//	session.domain     cookie domain
+//	session.secure     cookie secure flag
+//	session.http_only  cookie http-only flag, true by default
+func Sessions(store SessionStore) Handler {
+	return func(ctx *Context) {
+		cfg := ctx.app.config
+		name := cfg.String("session.cookie")
+		if name == "" {
+			name = "GOINK_SESSION"
+		}
+		s := &Session{store: store, data: make(map[string]interface{})}
+		if c, e := ctx.Request.Cookie(name); e == nil && c.Value != "" {
+			bytes, e := store.Read(c.Value)
+			if e != nil {
+				panic(e)
+			}
+			if bytes != nil && json.Unmarshal(bytes, &s.data) == nil {
+				s.id = c.Value
+			}
+		}
+		if s.id == "" {
+			s.id = randomString(16)
+			s.isNew = true
+		}
+		ctx.session = s
+		ctx.On(CONTEXT_BEFORE_SEND, func() {
+			saveSession(ctx, s, name)
+		})
+	}
+}
+
+func saveSession(ctx *Context, s *Session, name string) {
+	cfg := ctx.app.config
+	cookie := &http.Cookie{
+		Name:     name,
+		Path:     cfg.String("session.path"),
+		Domain:   cfg.String("session.domain"),
+		Secure:   cfg.Bool("session.secure"),
+		HttpOnly: cfg.String("session.http_only") == "" || cfg.Bool("session.http_only"),
+	}
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+	if s.isDestroy {
+		if !s.isNew {
+			cookie.MaxAge = -1
+			http.SetCookie(ctx.Response, cookie)
+		}
+		return
+	}
+	if !s.isChanged {
+		return
+	}
+	ttl := cfg.Int("session.ttl")
+	if ttl <= 0 {
+		ttl = 86400
+	}
+	// response is sending, so errors can't be thrown
+	bytes, e := json.Marshal(s.data)
+	if e != nil {
+		ctx.Logger().Error("session encode error", "error", e)
+		return
+	}
+	value, e := s.store.Write(s.id, bytes, time.Duration(ttl)*time.Second)
+	if e != nil {
+		ctx.Logger().Error("session write error", "error", e)
+		return
+	}
+	cookie.Value = value
+	cookie.MaxAge = ttl
+	cookie.Expires = time.Now().Add(time.Duration(ttl) * time.Second)
+	http.SetCookie(ctx.Response, cookie)
+}
@@ -0,0 +1,248 @@
+package GoInk
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var sessionIdRegexp = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// storeGC runs gc func of store every interval until Close.
+type storeGC struct {
+	stop chan struct{}
+	once sync.Once
+}
+
+// start runs gc in goroutine. Zero or negative interval doesn't start it.
+func (sg *storeGC) start(interval time.Duration, gc func()) {
+	if interval <= 0 {
+		return
+	}
+	sg.stop = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				gc()
+			case <-sg.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops gc goroutine of store.
+func (sg *storeGC) Close() error {
+	sg.once.Do(func() {
+		if sg.stop != nil {
+			close(sg.stop)
+		}
+	})
+	return nil
+}
+
+type memorySessionItem struct {
+	data   []byte
+	expire time.Time
+}
+
+// MemorySessionStore saves session data in memory.
+// Expired items are removed by gc goroutine, stopped by Close.
+type MemorySessionStore struct {
+	storeGC
+	items map[string]*memorySessionItem
+	lock  sync.RWMutex
+}
+
+// NewMemorySessionStore returns memory store and starts gc every interval duration.
+// Zero interval doesn't start gc, so call GC by yourself.
+func NewMemorySessionStore(interval time.Duration) *MemorySessionStore {
+	ms := new(MemorySessionStore)
+	ms.items = make(map[string]*memorySessionItem)
+	ms.start(interval, ms.GC)
+	return ms
+}
+
+// Read returns session data of id.
+func (ms *MemorySessionStore) Read(id string) ([]byte, error) {
+	ms.lock.RLock()
+	defer ms.lock.RUnlock()
+	item, ok := ms.items[id]
+	if !ok || time.Now().After(item.expire) {
+		return nil, nil
+	}
+	return item.data, nil
+}
+
+// Write saves session data of id.
+func (ms *MemorySessionStore) Write(id string, data []byte, ttl time.Duration) (string, error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	ms.items[id] = &memorySessionItem{data, time.Now().Add(ttl)}
+	return id, nil
+}
+
+// Remove deletes session data of id.
+func (ms *MemorySessionStore) Remove(id string) error {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	delete(ms.items, id)
+	return nil
+}
+
+// GC removes expired session items.
+func (ms *MemorySessionStore) GC() {
+	now := time.Now()
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	for id, item := range ms.items {
+		if now.After(item.expire) {
+			delete(ms.items, id)
+		}
+	}
+}
+
+// FileSessionStore saves session data as files in directory.
+// Each file starts with 8 bytes unix expire time.
+// Expired files are removed by gc goroutine, stopped by Close.
+type FileSessionStore struct {
+	storeGC
+	Dir string
+}
+
+// NewFileSessionStore returns file store in dir and starts gc every interval duration.
+// Zero interval doesn't start gc, so call GC by yourself.
+func NewFileSessionStore(dir string, interval time.Duration) (*FileSessionStore, error) {
+	if e := os.MkdirAll(dir, 0700); e != nil {
+		return nil, e
+	}
+	fs := &FileSessionStore{Dir: dir}
+	fs.start(interval, fs.GC)
+	return fs, nil
+}
+
+func (fs *FileSessionStore) file(id string) string {
+	return filepath.Join(fs.Dir, "sess_"+id)
+}
+
+// Read returns session data of id.
+func (fs *FileSessionStore) Read(id string) ([]byte, error) {
+	if !sessionIdRegexp.MatchString(id) {
+		return nil, nil
+	}
+	bytes, e := os.ReadFile(fs.file(id))
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil, nil
+		}
+		return nil, e
+	}
+	if len(bytes) < 8 || time.Now().Unix() > int64(binary.BigEndian.Uint64(bytes)) {
+		return nil, nil
+	}
+	return bytes[8:], nil
+}
+
+// Write saves session data of id.
+func (fs *FileSessionStore) Write(id string, data []byte, ttl time.Duration) (string, error) {
+	if !sessionIdRegexp.MatchString(id) {
+		return "", errors.New("invalid session id " + id)
+	}
+	bytes := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint64(bytes, uint64(time.Now().Add(ttl).Unix()))
+	bytes = append(bytes, data...)
+	// write to temp file and rename, so reading never gets half file
+	tmp := fs.file(id) + "." + randomString(4)
+	if e := os.WriteFile(tmp, bytes, 0600); e != nil {
+		return "", e
+	}
+	if e := os.Rename(tmp, fs.file(id)); e != nil {
+		os.Remove(tmp)
+		return "", e
+	}
+	return id, nil
+}
+
+// Remove deletes session data of id.
+func (fs *FileSessionStore) Remove(id string) error {
+	if !sessionIdRegexp.MatchString(id) {
+		return nil
+	}
+	e := os.Remove(fs.file(id))
+	if e != nil && !os.IsNotExist(e) {
+		return e
+	}
+	return nil
+}
+
+// GC removes expired session files.
+func (fs *FileSessionStore) GC() {
+	files, _ := filepath.Glob(filepath.Join(fs.Dir, "sess_*"))
+	now := time.Now().Unix()
+	for _, file := range files {
+		f, e := os.Open(file)
+		if e != nil {
+			continue
+		}
+		head := make([]byte, 8)
+		n, _ := f.Read(head)
+		f.Close()
+		if n < 8 || now > int64(binary.BigEndian.Uint64(head)) {
+			os.Remove(file)
+		}
+	}
+}
+
+// CookieSessionStore saves encrypted session data in cookie value itself.
+// The data is sealed by AES-GCM with key derived from secret, so it can't be read or changed by client.
+// Cookie value is limited about 4KB, keep session data small.
+type CookieSessionStore struct {
+	secret string
+}
+
+// NewCookieSessionStore returns cookie store with secret string.
+func NewCookieSessionStore(secret string) *CookieSessionStore {
+	return &CookieSessionStore{secret: secret}
+}
+
+// Read decrypts session data from cookie value.
+// Tampered or expired value returns nil data.
+func (cs *CookieSessionStore) Read(value string) ([]byte, error) {
+	raw, e := base64.RawURLEncoding.DecodeString(value)
+	if e != nil {
+		return nil, nil
+	}
+	bytes, e := decrypt(newAEAD(cs.secret), raw, nil)
+	if e != nil || len(bytes) < 8 {
+		return nil, nil
+	}
+	if time.Now().Unix() > int64(binary.BigEndian.Uint64(bytes)) {
+		return nil, nil
+	}
+	return bytes[8:], nil
+}
+
+// Write encrypts session data with expire time as cookie value.
+func (cs *CookieSessionStore) Write(id string, data []byte, ttl time.Duration) (string, error) {
+	bytes := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint64(bytes, uint64(time.Now().Add(ttl).Unix()))
+	bytes = append(bytes, data...)
+	value := base64.RawURLEncoding.EncodeToString(encrypt(newAEAD(cs.secret), bytes, nil))
+	if len(value) > 4000 {
+		return "", errors.New("session data is too large for cookie")
+	}
+	return value, nil
+}
+
+// Remove does nothing, the cookie is deleted by session.
+func (cs *CookieSessionStore) Remove(value string) error {
+	return nil
+}
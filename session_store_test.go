@@ -0,0 +1,37 @@
+package GoInk
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestMemorySessionStore(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ms := NewMemorySessionStore(time.Millisecond)
+	id := randomString(16)
+	if _, e := ms.Write(id, []byte("data"), time.Hour); e != nil {
+		t.Fatal(e)
+	}
+	ms.Write("old", []byte("data"), -time.Second)
+	if data, _ := ms.Read(id); string(data) != "data" {
+		t.Fatalf("Read = %q, want data", data)
+	}
+	if data, _ := ms.Read("old"); data != nil {
+		t.Fatalf("Read expired = %q, want nil", data)
+	}
+	time.Sleep(10 * time.Millisecond)
+	ms.lock.RLock()
+	_, ok := ms.items["old"]
+	ms.lock.RUnlock()
+	if ok {
+		t.Fatal("expired item is not removed by gc")
+	}
+
+	ms.Close()
+	ms.Close()
+	time.Sleep(10 * time.Millisecond)
+	if n := runtime.NumGoroutine(); n > before {
+		t.Fatalf("%d goroutines after Close, want %d", n, before)
+	}
+}
@@ -0,0 +1,209 @@
+package GoInk
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StaticOption defines static file server settings.
+type StaticOption struct {
+	// Url prefix, as "/static/"
+	Prefix string
+	// Serving directory, used if FS is nil
+	Dir string
+	// Serving file system, such as embed.FS
+	FS fs.FS
+	// Index file names of directory
+	Index []string
+	// List directory files if no index file
+	Listing bool
+	// Cache-Control value by file extension as ".css", key "*" is default
+	CacheControl map[string]string
+	// Serve .br or .gz file if exists and accepted by client
+	Precompressed bool
+}
+
+// NewStaticOption returns static option from config:
+//
+//	static.prefix         "/static/" by default
+//	static.dir            "static" by default
+//	static.index          "index.html" by default, separated by comma
+//	static.listing
+//	static.precompressed
+//	static.cache_control  default Cache-Control value
+func NewStaticOption(cfg *Config) *StaticOption {
+	opt := &StaticOption{
+		Prefix:        cfg.String("static.prefix"),
+		Dir:           cfg.String("static.dir"),
+		Index:         splitConfig(cfg.String("static.index")),
+		Listing:       cfg.Bool("static.listing"),
+		Precompressed: cfg.Bool("static.precompressed"),
+		CacheControl:  make(map[string]string),
+	}
+	if opt.Prefix == "" {
+		opt.Prefix = "/static/"
+	}
+	if opt.Dir == "" {
+		opt.Dir = "static"
+	}
+	if len(opt.Index) < 1 {
+		opt.Index = []string{"index.html"}
+	}
+	if cc := cfg.String("static.cache_control"); cc != "" {
+		opt.CacheControl["*"] = cc
+	}
+	return opt
+}
+
+// StaticHandler returns handler serving files under prefix url, registered by App.Static.
+// Nil option loads settings by NewStaticOption from app config.
+// It supports ETag, Last-Modified and Range requests by http.ServeContent.
+// Requests out of prefix or not GET and HEAD are passed to next handlers.
+func StaticHandler(opt *StaticOption) Handler {
+	var once sync.Once
+	return func(ctx *Context) {
+		once.Do(func() {
+			if opt == nil {
+				opt = NewStaticOption(ctx.app.config)
+			}
+			if opt.FS == nil {
+				opt.FS = os.DirFS(opt.Dir)
+			}
+		})
+		if ctx.Method != "GET" && ctx.Method != "HEAD" {
+			return
+		}
+		if !strings.HasPrefix(ctx.Url, opt.Prefix) {
+			return
+		}
+		// cleaning rooted path removes all "..", so name never leaves file system
+		name := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(ctx.Url, opt.Prefix)), "/")
+		if name == "" {
+			name = "."
+		}
+		serveStatic(ctx, opt, name)
+	}
+}
+
+func serveStatic(ctx *Context, opt *StaticOption, name string) {
+	info, e := fs.Stat(opt.FS, name)
+	if e != nil {
+		return
+	}
+	if info.IsDir() {
+		if !strings.HasSuffix(ctx.Url, "/") {
+			ctx.Redirect(ctx.Url+"/", 301)
+			ctx.End()
+			return
+		}
+		for _, index := range opt.Index {
+			file := path.Join(name, index)
+			if fi, e := fs.Stat(opt.FS, file); e == nil && !fi.IsDir() {
+				serveStaticFile(ctx, opt, file, fi)
+				return
+			}
+		}
+		if opt.Listing {
+			listStaticDir(ctx, opt, name)
+		}
+		return
+	}
+	serveStaticFile(ctx, opt, name, info)
+}
+
+func serveStaticFile(ctx *Context, opt *StaticOption, name string, info fs.FileInfo) {
+	file, encoding := name, ""
+	if opt.Precompressed {
+		accept := ctx.GetHeader("Accept-Encoding")
+		for _, enc := range []struct{ name, ext string }{{"br", ".br"}, {"gzip", ".gz"}} {
+			if !acceptEncoding(accept, enc.name) {
+				continue
+			}
+			if fi, e := fs.Stat(opt.FS, name+enc.ext); e == nil && !fi.IsDir() {
+				file, info, encoding = name+enc.ext, fi, enc.name
+				break
+			}
+		}
+	}
+	f, e := opt.FS.Open(file)
+	if e != nil {
+		return
+	}
+	defer f.Close()
+	header := ctx.Response.Header()
+	ctx.copyHeader()
+	ctype := mime.TypeByExtension(path.Ext(name))
+	if ctype == "" {
+		ctype = "application/octet-stream"
+	}
+	header.Set("Content-Type", ctype)
+	if cc, ok := opt.CacheControl[path.Ext(name)]; ok {
+		header.Set("Cache-Control", cc)
+	} else if cc, ok := opt.CacheControl["*"]; ok {
+		header.Set("Cache-Control", cc)
+	}
+	if opt.Precompressed {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if encoding != "" {
+		header.Set("Content-Encoding", encoding)
+	}
+	header.Set("ETag", fmt.Sprintf(`W/"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	ctx.serveContent(path.Base(name), info.ModTime(), f)
+}
+
+// acceptEncoding checks encoding name in Accept-Encoding header and not disabled by q=0.
+func acceptEncoding(accept string, name string) bool {
+	for _, item := range strings.Split(accept, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ";")
+		if !strings.EqualFold(strings.TrimSpace(parts[0]), name) {
+			continue
+		}
+		for _, p := range parts[1:] {
+			p = strings.Replace(p, " ", "", -1)
+			if p == "q=0" || p == "q=0.0" || p == "q=0.00" || p == "q=0.000" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+var staticListTemplate = template.Must(template.New("list").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Index of {{.Path}}</title></head>
+<body><h1>Index of {{.Path}}</h1><ul>
+{{range .Files}}<li><a href="{{.}}">{{.}}</a></li>
+{{end}}</ul></body></html>`))
+
+func listStaticDir(ctx *Context, opt *StaticOption, name string) {
+	entries, e := fs.ReadDir(opt.FS, name)
+	if e != nil {
+		return
+	}
+	files := make([]string, 0, len(entries)+1)
+	if name != "." {
+		files = append(files, "../")
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			files = append(files, entry.Name()+"/")
+		} else {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+	var buf strings.Builder
+	if e := staticListTemplate.Execute(&buf, map[string]interface{}{"Path": ctx.Url, "Files": files}); e != nil {
+		panic(e)
+	}
+	ctx.Body = []byte(buf.String())
+	ctx.End()
+}
@@ -0,0 +1,58 @@
+package GoInk
+
+import (
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestStaticHandler(t *testing.T) {
+	app := New()
+	app.Use(RequestId(nil))
+	app.Static(StaticHandler(&StaticOption{
+		Prefix: "/static/",
+		FS: fstest.MapFS{
+			"app.css":        {Data: []byte("body{}")},
+			"doc/index.html": {Data: []byte("index")},
+		},
+		Index: []string{"index.html"},
+	}))
+	app.Get("/static/route/", func(ctx *Context) {
+		ctx.Body = []byte("route")
+	})
+
+	tests := []struct {
+		name   string
+		url    string
+		rng    string
+		status int
+		body   string
+	}{
+		{"file", "/static/app.css", "", 200, "body{}"},
+		{"range", "/static/app.css", "bytes=0-3", 206, "body"},
+		{"index", "/static/doc/", "", 200, "index"},
+		{"dir redirect", "/static/doc", "", 301, ""},
+		{"traversal", "/static/../static.go", "", 404, ""},
+		{"missing passes to route", "/static/route/", "", 200, "route"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.rng != "" {
+				req.Header.Set("Range", tt.rng)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Fatalf("body = %q, want %q", w.Body.String(), tt.body)
+			}
+			// middleware runs before static handler
+			if w.Header().Get("X-Request-ID") == "" {
+				t.Fatal("X-Request-ID is not set")
+			}
+		})
+	}
+}
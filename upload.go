@@ -0,0 +1,156 @@
+package GoInk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUploadMissing means no uploaded file of given key.
+	ErrUploadMissing = errors.New("upload file is missing")
+	// ErrUploadSize means uploaded file is larger than allowed size.
+	ErrUploadSize = errors.New("upload file is too large")
+	// ErrUploadType means detected mime type of uploaded file is not allowed.
+	ErrUploadType = errors.New("upload file type is not allowed")
+
+	unsafeFileChar = regexp.MustCompile(`[^\w.\-]+`)
+)
+
+// UploadFile wraps uploaded multipart file header.
+type UploadFile struct {
+	*multipart.FileHeader
+	mime string
+}
+
+// File returns first uploaded file of given key.
+func (ctx *Context) File(key string) (*UploadFile, error) {
+	files := ctx.Files(key)
+	if len(files) < 1 {
+		return nil, ErrUploadMissing
+	}
+	return files[0], nil
+}
+
+// Files returns all uploaded files of given key.
+func (ctx *Context) Files(key string) []*UploadFile {
+	ctx.ParseForm()
+	form := ctx.Request.MultipartForm
+	if form == nil || len(form.File[key]) < 1 {
+		return nil
+	}
+	files := make([]*UploadFile, len(form.File[key]))
+	for i, fh := range form.File[key] {
+		files[i] = &UploadFile{FileHeader: fh}
+	}
+	return files
+}
+
+// Mime detects file mime type by content.
+func (f *UploadFile) Mime() (string, error) {
+	if f.mime != "" {
+		return f.mime, nil
+	}
+	rd, e := f.Open()
+	if e != nil {
+		return "", e
+	}
+	defer rd.Close()
+	buf := make([]byte, 512)
+	n, e := io.ReadFull(rd, buf)
+	if e != nil && e != io.ErrUnexpectedEOF && e != io.EOF {
+		return "", e
+	}
+	f.mime = http.DetectContentType(buf[:n])
+	return f.mime, nil
+}
+
+// Check validates file size and detected mime type.
+// Zero maxSize means no size limit. Empty mimes means any type.
+// Mime item can be prefix as "image/".
+func (f *UploadFile) Check(maxSize int64, mimes ...string) error {
+	if maxSize > 0 && f.Size > maxSize {
+		return ErrUploadSize
+	}
+	if len(mimes) < 1 {
+		return nil
+	}
+	mime, e := f.Mime()
+	if e != nil {
+		return e
+	}
+	mime = strings.TrimSpace(strings.Split(mime, ";")[0])
+	for _, m := range mimes {
+		if mime == m || (strings.HasSuffix(m, "/") && strings.HasPrefix(mime, m)) {
+			return nil
+		}
+	}
+	return ErrUploadType
+}
+
+// Save saves file to directory with sanitized file name.
+// If the name exists, a number suffix is added. It returns saved file path.
+func (f *UploadFile) Save(dir string) (string, error) {
+	name := SanitizeFileName(f.Filename)
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 0; i < 1000; i++ {
+		file := filepath.Join(dir, name)
+		e := f.save(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+		if e == nil {
+			return file, nil
+		}
+		if !os.IsExist(e) {
+			return "", e
+		}
+		name = base + "-" + strconv.Itoa(i+1) + ext
+	}
+	return "", fmt.Errorf("too many files named %s in %s", f.Filename, dir)
+}
+
+// SaveAs saves file to given path, overwriting existing file.
+func (f *UploadFile) SaveAs(file string) error {
+	return f.save(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+}
+
+func (f *UploadFile) save(file string, flag int) error {
+	src, e := f.Open()
+	if e != nil {
+		return e
+	}
+	defer src.Close()
+	dst, e := os.OpenFile(file, flag, 0644)
+	if e != nil {
+		return e
+	}
+	_, e = io.Copy(dst, src)
+	if e1 := dst.Close(); e == nil {
+		e = e1
+	}
+	if e != nil {
+		os.Remove(file)
+	}
+	return e
+}
+
+// SanitizeFileName returns safe file name without directory and special chars.
+// The result only contains letters, numbers, dot, underscore and dash.
+func SanitizeFileName(name string) string {
+	name = strings.Replace(name, "\\", "/", -1)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = unsafeFileChar.ReplaceAllString(name, "_")
+	name = strings.TrimLeft(name, ".")
+	if name == "" || strings.Trim(name, "_") == "" {
+		name = "file"
+	}
+	return name
+}
@@ -0,0 +1,428 @@
+package GoInk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	goUrl "net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	VALIDATE_TAG = "validate"
+	BIND_TAG     = "form"
+	LABEL_TAG    = "label"
+)
+
+// ValidateRule checks a field value with rule param string.
+// It returns false if the value is invalid.
+type ValidateRule func(value reflect.Value, param string) bool
+
+// FieldError is a failed validation rule on one field.
+type FieldError struct {
+	// Field path, as Name, Address.City or Items[0].Title
+	Field string `json:"field"`
+	// Field label from label tag, or field path
+	Label string `json:"-"`
+	// Failed rule name
+	Rule string `json:"rule"`
+	// Rule param string
+	Param string `json:"param,omitempty"`
+	// Translated message
+	Message string `json:"message"`
+}
+
+// Error returns error message.
+func (fe *FieldError) Error() string {
+	return fe.Message
+}
+
+// ValidationErrors is field errors slice returned by Validator.
+type ValidationErrors []*FieldError
+
+// Error returns all messages joined by semicolon.
+func (ve ValidationErrors) Error() string {
+	msg := make([]string, len(ve))
+	for i, fe := range ve {
+		msg[i] = fe.Message
+	}
+	return strings.Join(msg, "; ")
+}
+
+// Has checks field error existing.
+func (ve ValidationErrors) Has(field string) bool {
+	return ve.Get(field) != ""
+}
+
+// Get returns first error message of field.
+// It's convenient in template, as {{.Errors.Get "Name"}}.
+func (ve ValidationErrors) Get(field string) string {
+	for _, fe := range ve {
+		if fe.Field == field {
+			return fe.Message
+		}
+	}
+	return ""
+}
+
+// Map returns field to first message map.
+func (ve ValidationErrors) Map() map[string]string {
+	data := make(map[string]string)
+	for _, fe := range ve {
+		if _, ok := data[fe.Field]; !ok {
+			data[fe.Field] = fe.Message
+		}
+	}
+	return data
+}
+
+// Validator instance checks struct fields by validate tag.
+// Rules are separated by comma, as `validate:"required,min=3,max=20"`.
+// Rule regex takes the rest of tag as param, so it should be the last one.
+type Validator struct {
+	rules    map[string]ValidateRule
+	messages map[string]string
+	regexps  sync.Map
+	lock     sync.RWMutex
+	// Translate converts field error to message.
+	// If nil, use message format registered by Message.
+	Translate func(fe *FieldError) string
+}
+
+// NewValidator returns validator instance with bundle rules:
+// required, min, max, len, regex, email, url and oneof.
+func NewValidator() *Validator {
+	v := new(Validator)
+	v.rules = make(map[string]ValidateRule)
+	v.messages = make(map[string]string)
+	v.Rule("required", validateRequired, "{field} is required")
+	v.Rule("min", validateMin, "{field} must be at least {param}")
+	v.Rule("max", validateMax, "{field} must be at most {param}")
+	v.Rule("len", validateLen, "{field} length must be {param}")
+	v.Rule("regex", v.validateRegex, "{field} format is invalid")
+	v.Rule("email", validateEmail, "{field} must be a valid email")
+	v.Rule("url", validateUrl, "{field} must be a valid url")
+	v.Rule("oneof", validateOneOf, "{field} must be one of {param}")
+	return v
+}
+
+// Rule registers custom rule with name and message format.
+// Message format supports {field} and {param} placeholders.
+func (v *Validator) Rule(name string, fn ValidateRule, message string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.rules[name] = fn
+	v.messages[name] = message
+}
+
+// Message sets message format of rule name, such as translation.
+func (v *Validator) Message(name string, message string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.messages[name] = message
+}
+
+// Validate checks struct or struct pointer.
+// It returns nil if all fields are valid.
+func (v *Validator) Validate(data interface{}) ValidationErrors {
+	rv := reflect.Indirect(reflect.ValueOf(data))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	errs := make(ValidationErrors, 0)
+	v.validateStruct(rv, "", &errs)
+	if len(errs) < 1 {
+		return nil
+	}
+	return errs
+}
+
+func (v *Validator) validateStruct(rv reflect.Value, prefix string, errs *ValidationErrors) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := prefix + field.Name
+		value := rv.Field(i)
+		if tag := field.Tag.Get(VALIDATE_TAG); tag != "" && tag != "-" {
+			label := field.Tag.Get(LABEL_TAG)
+			if label == "" {
+				label = name
+			}
+			if !v.validateField(value, tag, name, label, errs) {
+				continue
+			}
+		}
+		v.validateNested(value, name, errs)
+	}
+}
+
+// validateNested walks into struct, struct pointer and slice of structs.
+func (v *Validator) validateNested(value reflect.Value, name string, errs *ValidationErrors) {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Struct:
+		v.validateStruct(value, name+".", errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			item := reflect.Indirect(value.Index(i))
+			if item.Kind() == reflect.Struct {
+				v.validateStruct(item, name+"["+strconv.Itoa(i)+"].", errs)
+			}
+		}
+	}
+}
+
+// validateField checks one field with tag rules.
+// It stops at first failed rule and returns false.
+func (v *Validator) validateField(value reflect.Value, tag string, name string, label string, errs *ValidationErrors) bool {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	for tag != "" {
+		var item string
+		if strings.HasPrefix(tag, "regex=") {
+			item, tag = tag, ""
+		} else if i := strings.Index(tag, ","); i >= 0 {
+			item, tag = tag[:i], tag[i+1:]
+		} else {
+			item, tag = tag, ""
+		}
+		rule, param := item, ""
+		if i := strings.Index(item, "="); i >= 0 {
+			rule, param = item[:i], item[i+1:]
+		}
+		// empty optional value skips other rules
+		if rule != "required" && isEmptyValue(value) {
+			continue
+		}
+		fn, ok := v.rules[rule]
+		if !ok {
+			panic("unknown validate rule " + rule + " on " + name)
+		}
+		if fn(value, param) {
+			continue
+		}
+		fe := &FieldError{Field: name, Label: label, Rule: rule, Param: param}
+		fe.Message = v.message(fe)
+		*errs = append(*errs, fe)
+		return false
+	}
+	return true
+}
+
+func (v *Validator) message(fe *FieldError) string {
+	if v.Translate != nil {
+		if msg := v.Translate(fe); msg != "" {
+			return msg
+		}
+	}
+	msg, ok := v.messages[fe.Rule]
+	if !ok {
+		msg = "{field} is invalid"
+	}
+	return strings.NewReplacer("{field}", fe.Label, "{param}", fe.Param).Replace(msg)
+}
+
+func (v *Validator) validateRegex(value reflect.Value, param string) bool {
+	reg, ok := v.regexps.Load(param)
+	if !ok {
+		reg, _ = v.regexps.LoadOrStore(param, regexp.MustCompile(param))
+	}
+	return reg.(*regexp.Regexp).MatchString(valueString(value))
+}
+
+func valueString(value reflect.Value) string {
+	return fmt.Sprint(reflect.Indirect(value).Interface())
+}
+
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	}
+	return value.IsZero()
+}
+
+func validateRequired(value reflect.Value, param string) bool {
+	return !isEmptyValue(value)
+}
+
+// compareValue compares length of string, slice and map, or number value with param.
+// It returns -1, 0 or 1 as value is less, equal or greater.
+func compareValue(value reflect.Value, param string) int {
+	value = reflect.Indirect(value)
+	var f float64
+	switch value.Kind() {
+	case reflect.String:
+		f = float64(len([]rune(value.String())))
+	case reflect.Slice, reflect.Map, reflect.Array:
+		f = float64(value.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = value.Float()
+	default:
+		panic("unsupported validate value type " + value.Type().String())
+	}
+	p, e := strconv.ParseFloat(param, 64)
+	if e != nil {
+		panic("invalid validate rule param " + param)
+	}
+	switch {
+	case f < p:
+		return -1
+	case f > p:
+		return 1
+	}
+	return 0
+}
+
+func validateMin(value reflect.Value, param string) bool {
+	return compareValue(value, param) >= 0
+}
+
+func validateMax(value reflect.Value, param string) bool {
+	return compareValue(value, param) <= 0
+}
+
+func validateLen(value reflect.Value, param string) bool {
+	return compareValue(value, param) == 0
+}
+
+func validateEmail(value reflect.Value, param string) bool {
+	str := valueString(value)
+	addr, e := mail.ParseAddress(str)
+	return e == nil && addr.Address == str
+}
+
+func validateUrl(value reflect.Value, param string) bool {
+	u, e := goUrl.ParseRequestURI(valueString(value))
+	return e == nil && u.Scheme != "" && u.Host != ""
+}
+
+func validateOneOf(value reflect.Value, param string) bool {
+	str := valueString(value)
+	for _, p := range strings.Fields(param) {
+		if p == str {
+			return true
+		}
+	}
+	return false
+}
+
+// Bind fills struct pointer with request input, then validates it.
+// Json body is decoded if request content type is json,
+// otherwise form values are assigned to fields by form tag or field name.
+// It returns ValidationErrors if validation failed.
+func (ctx *Context) Bind(data interface{}) error {
+	if e := ctx.ParseForm(); e != nil {
+		return e
+	}
+	if strings.HasPrefix(ctx.GetHeader("Content-Type"), "application/json") {
+		if e := json.NewDecoder(ctx.Request.Body).Decode(data); e != nil {
+			return e
+		}
+	} else if e := bindForm(reflect.ValueOf(data), ctx.Request.Form); e != nil {
+		return e
+	}
+	if errs := ctx.Validate(data); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// Validate checks struct by app validator.
+func (ctx *Context) Validate(data interface{}) ValidationErrors {
+	return ctx.app.validator.Validate(data)
+}
+
+// ValidateJson sets 422 json response with field errors.
+// The json is as {"errors":[{"field":"Name","rule":"required","message":"Name is required"}]}.
+func (ctx *Context) ValidateJson(errs ValidationErrors) {
+	ctx.Json(map[string]interface{}{"errors": errs})
+	ctx.Status = 422
+}
+
+func bindForm(rv reflect.Value, form goUrl.Values) error {
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be struct pointer")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Tag.Get(BIND_TAG)
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		values, ok := form[name]
+		if !ok || len(values) < 1 {
+			continue
+		}
+		value := rv.Field(i)
+		if value.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(value.Type(), len(values), len(values))
+			for j, str := range values {
+				if e := setValue(slice.Index(j), str); e != nil {
+					return fmt.Errorf("bind %s: %v", name, e)
+				}
+			}
+			value.Set(slice)
+			continue
+		}
+		if e := setValue(value, values[0]); e != nil {
+			return fmt.Errorf("bind %s: %v", name, e)
+		}
+	}
+	return nil
+}
+
+func setValue(value reflect.Value, str string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(str)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, e := strconv.ParseInt(str, 10, value.Type().Bits())
+		if e != nil {
+			return e
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, e := strconv.ParseUint(str, 10, value.Type().Bits())
+		if e != nil {
+			return e
+		}
+		value.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, e := strconv.ParseFloat(str, value.Type().Bits())
+		if e != nil {
+			return e
+		}
+		value.SetFloat(f)
+	case reflect.Bool:
+		b, e := strconv.ParseBool(str)
+		if e != nil {
+			return e
+		}
+		value.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package GoInk
 import (
 	"bytes"
 	"html/template"
+	"io/fs"
 	"os"
 	"path"
 	"strings"
@@ -12,6 +13,8 @@ import (
 type View struct {
 	// template directory
 	Dir string
+	// template file system, such as embed.FS. If nil, use Dir in OS file system
+	FS fs.FS
 	// view functions map
 	FuncMap template.FuncMap
 	// Cache Flag
@@ -28,19 +31,21 @@ func (v *View) getTemplateInstance(tpl []string) (*template.Template, error) {
 			return v.templateCache[key], nil
 		}
 	}
-	var (
-		t    *template.Template
-		e    error
-		file []string = make([]string, len(tpl))
-	)
-	for i, tp := range tpl {
-		file[i] = path.Join(v.Dir, tp)
-	}
-	t = template.New(path.Base(tpl[0]))
+	t := template.New(path.Base(tpl[0]))
 	t.Funcs(v.FuncMap)
-	t, e = t.ParseFiles(file...)
-	if e != nil {
-		return nil, e
+	// parse files one by one as ParseFiles, so names are literal, not glob patterns
+	for _, tp := range tpl {
+		b, e := v.readFile(tp)
+		if e != nil {
+			return nil, e
+		}
+		tmpl := t
+		if name := path.Base(tp); name != t.Name() {
+			tmpl = t.New(name)
+		}
+		if _, e = tmpl.Parse(string(b)); e != nil {
+			return nil, e
+		}
 	}
 	if v.IsCache {
 		v.templateCache[key] = t
@@ -66,11 +71,22 @@ func (v *View) Render(tpl string, data map[string]interface{}) ([]byte, error) {
 
 // Has checks the template file existing.
 func (v *View) Has(tpl string) bool {
-	f := path.Join(v.Dir, tpl)
-	_, e := os.Stat(f)
+	if v.FS != nil {
+		_, e := fs.Stat(v.FS, path.Clean(tpl))
+		return e == nil
+	}
+	_, e := os.Stat(path.Join(v.Dir, tpl))
 	return e == nil
 }
 
+// readFile returns template file content from FS, or under Dir in OS file system.
+func (v *View) readFile(tpl string) ([]byte, error) {
+	if v.FS != nil {
+		return fs.ReadFile(v.FS, path.Clean(tpl))
+	}
+	return os.ReadFile(path.Join(v.Dir, tpl))
+}
+
 // NoCache sets view cache off and clean cached data.
 func (v *View) NoCache(){
 	v.IsCache = false
@@ -90,3 +106,10 @@ func NewView(dir string) *View {
 	v.templateCache = make(map[string]*template.Template)
 	return v
 }
+
+// NewViewFS returns view instance with file system, such as embed.FS.
+func NewViewFS(fsys fs.FS) *View {
+	v := NewView("")
+	v.FS = fsys
+	return v
+}
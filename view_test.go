@@ -0,0 +1,44 @@
+package GoInk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestViewRender(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "view"), 0700)
+	os.WriteFile(filepath.Join(dir, "view", "page[1].html"), []byte(`page {{template "part.html" .}}`), 0600)
+	os.WriteFile(filepath.Join(dir, "part.html"), []byte(`{{.Name}}`), 0600)
+
+	fsView := NewViewFS(fstest.MapFS{
+		"page[1].html": {Data: []byte(`page {{template "part.html" .}}`)},
+		"pagex.html":   {Data: []byte(`glob`)},
+		"part.html":    {Data: []byte(`{{.Name}}`)},
+	})
+	tests := []struct {
+		name string
+		view *View
+		tpl  string
+	}{
+		// names are literal, not glob patterns
+		{"dir", NewView(filepath.Join(dir, "view")), "page[1].html,../part.html"},
+		{"fs", fsView, "page[1].html,part.html"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, e := tt.view.Render(tt.tpl, map[string]interface{}{"Name": "ink"})
+			if e != nil {
+				t.Fatal(e)
+			}
+			if string(b) != "page ink" {
+				t.Fatalf("Render = %q, want %q", b, "page ink")
+			}
+		})
+	}
+	if fsView.Has("page?.html") || !fsView.Has("page[1].html") {
+		t.Fatal("Has should check literal name")
+	}
+}